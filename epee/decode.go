@@ -0,0 +1,606 @@
+package epee
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var (
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+
+	errUnexpectedEOF = errors.New("unexpected end of input")
+)
+
+// Unmarshal decodes the portable storage payload `data` into the value
+// pointed to by `v`.
+//
+// Entries without a corresponding field are ignored, and integers of any
+// type are accepted for any integer field as long as the value fits.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("unmarshal: non-nil pointer required, got %T", v)
+	}
+
+	root, err := parse(data)
+	if err != nil {
+		return err
+	}
+
+	return assign(root, rv.Elem())
+}
+
+// section is the parsed representation of an object.
+type section struct {
+	entries []entry
+}
+
+// entry is a named value in a section.
+type entry struct {
+	name  string
+	value interface{}
+}
+
+// array is the parsed representation of an array.
+type array struct {
+	elemType byte
+	items    []interface{}
+}
+
+// parse parses a full payload, header included, into its root section.
+func parse(data []byte) (*section, error) {
+	if len(data) < headerSize {
+		return nil, fmt.Errorf("header: %w", errUnexpectedEOF)
+	}
+
+	if binary.LittleEndian.Uint32(data[0:]) != signatureA ||
+		binary.LittleEndian.Uint32(data[4:]) != signatureB {
+		return nil, fmt.Errorf("header: signature mismatch")
+	}
+
+	if data[8] != formatVersion {
+		return nil, fmt.Errorf("header: unsupported version %d", data[8])
+	}
+
+	p := &parser{data: data[headerSize:]}
+
+	root, err := p.readSection(0)
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
+type parser struct {
+	data []byte
+}
+
+func (p *parser) next(n int) ([]byte, error) {
+	if n < 0 || len(p.data) < n {
+		return nil, errUnexpectedEOF
+	}
+
+	b := p.data[:n]
+	p.data = p.data[n:]
+
+	return b, nil
+}
+
+func (p *parser) readVarint() (uint64, error) {
+	v, n, err := readVarint(p.data)
+	if err != nil {
+		return 0, err
+	}
+
+	p.data = p.data[n:]
+
+	return v, nil
+}
+
+// readCount reads the number of elements of a section or array, making sure
+// that there are enough bytes left for them given the minimum size of each.
+func (p *parser) readCount(minSize int) (int, error) {
+	count, err := p.readVarint()
+	if err != nil {
+		return 0, err
+	}
+
+	if count > uint64(len(p.data)/minSize) {
+		return 0, fmt.Errorf("count %d exceeds remaining input: %w",
+			count, errUnexpectedEOF)
+	}
+
+	return int(count), nil
+}
+
+func (p *parser) readSection(depth int) (*section, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("exceeded max depth of %d", maxDepth)
+	}
+
+	// name length, type and at least one byte of value.
+	//
+	const minEntrySize = 3
+
+	count, err := p.readCount(minEntrySize)
+	if err != nil {
+		return nil, fmt.Errorf("section: %w", err)
+	}
+
+	s := &section{entries: make([]entry, 0, count)}
+
+	for i := 0; i < count; i++ {
+		nameSize, err := p.next(1)
+		if err != nil {
+			return nil, fmt.Errorf("entry name size: %w", err)
+		}
+
+		name, err := p.next(int(nameSize[0]))
+		if err != nil {
+			return nil, fmt.Errorf("entry name: %w", err)
+		}
+
+		typ, err := p.next(1)
+		if err != nil {
+			return nil, fmt.Errorf("entry '%s' type: %w", name, err)
+		}
+
+		var value interface{}
+
+		if typ[0]&FlagArray != 0 {
+			value, err = p.readArray(typ[0]&^FlagArray, depth)
+		} else {
+			value, err = p.readValue(typ[0], depth)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("entry '%s': %w", name, err)
+		}
+
+		s.entries = append(s.entries, entry{
+			name: string(name), value: value,
+		})
+	}
+
+	return s, nil
+}
+
+func (p *parser) readArray(elemType byte, depth int) (*array, error) {
+	if depth > maxDepth {
+		return nil, fmt.Errorf("exceeded max depth of %d", maxDepth)
+	}
+
+	minSize, ok := minValueSize(elemType)
+	if !ok {
+		return nil, fmt.Errorf("unknown array element type %d", elemType)
+	}
+
+	count, err := p.readCount(minSize)
+	if err != nil {
+		return nil, fmt.Errorf("array: %w", err)
+	}
+
+	a := &array{elemType: elemType, items: make([]interface{}, count)}
+
+	for i := 0; i < count; i++ {
+		var item interface{}
+
+		if elemType == TypeArray {
+			typ, err := p.next(1)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+
+			if typ[0]&FlagArray == 0 {
+				return nil, fmt.Errorf("index %d: expected array "+
+					"type, got %d", i, typ[0])
+			}
+
+			item, err = p.readArray(typ[0]&^FlagArray, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		} else {
+			item, err = p.readValue(elemType, depth)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+
+		a.items[i] = item
+	}
+
+	return a, nil
+}
+
+// minValueSize gives the minimum number of bytes that a value of the type
+// `typ` takes when encoded in an array.
+func minValueSize(typ byte) (int, bool) {
+	switch typ {
+	case TypeInt64, TypeUint64, TypeDouble:
+		return 8, true
+	case TypeInt32, TypeUint32:
+		return 4, true
+	case TypeInt16, TypeUint16:
+		return 2, true
+	case TypeInt8, TypeUint8, TypeBool, TypeString, TypeObject:
+		return 1, true
+	case TypeArray:
+		return 2, true
+	}
+
+	return 0, false
+}
+
+func (p *parser) readValue(typ byte, depth int) (interface{}, error) {
+	if typ == TypeObject {
+		return p.readSection(depth + 1)
+	}
+
+	if typ == TypeString {
+		size, err := p.readVarint()
+		if err != nil {
+			return nil, err
+		}
+
+		if size > uint64(len(p.data)) {
+			return nil, fmt.Errorf("string of size %d: %w",
+				size, errUnexpectedEOF)
+		}
+
+		b, _ := p.next(int(size))
+		return string(b), nil
+	}
+
+	size, ok := minValueSize(typ)
+	if !ok || typ == TypeArray {
+		return nil, fmt.Errorf("unknown type %d", typ)
+	}
+
+	b, err := p.next(size)
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case TypeInt64:
+		return int64(binary.LittleEndian.Uint64(b)), nil
+	case TypeInt32:
+		return int32(binary.LittleEndian.Uint32(b)), nil
+	case TypeInt16:
+		return int16(binary.LittleEndian.Uint16(b)), nil
+	case TypeInt8:
+		return int8(b[0]), nil
+	case TypeUint64:
+		return binary.LittleEndian.Uint64(b), nil
+	case TypeUint32:
+		return binary.LittleEndian.Uint32(b), nil
+	case TypeUint16:
+		return binary.LittleEndian.Uint16(b), nil
+	case TypeUint8:
+		return b[0], nil
+	case TypeDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case TypeBool:
+		return b[0] != 0, nil
+	}
+
+	return nil, fmt.Errorf("unknown type %d", typ)
+}
+
+// generic converts a parsed value into the representation used when
+// unmarshalling into an `interface{}`.
+func generic(v interface{}) interface{} {
+	switch value := v.(type) {
+	case *section:
+		m := make(map[string]interface{}, len(value.entries))
+		for _, e := range value.entries {
+			m[e.name] = generic(e.value)
+		}
+
+		return m
+	case *array:
+		items := make([]interface{}, len(value.items))
+		for idx, item := range value.items {
+			items[idx] = generic(item)
+		}
+
+		return items
+	}
+
+	return v
+}
+
+// typeName gives a human readable name of the type of a parsed value.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case *section:
+		return "object"
+	case *array:
+		return "array"
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+// assign sets `rv` to the parsed value `v`.
+func assign(v interface{}, rv reflect.Value) error {
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() &&
+		reflect.PtrTo(rv.Type()).Implements(unmarshalerType) {
+		rv = rv.Addr()
+	}
+
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		if rv.Type().Implements(unmarshalerType) {
+			err := rv.Interface().(Unmarshaler).UnmarshalEpee(generic(v))
+			if err != nil {
+				return fmt.Errorf("unmarshal epee: %w", err)
+			}
+
+			return nil
+		}
+
+		return assign(v, rv.Elem())
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("cannot unmarshal %s into %s",
+			typeName(v), rv.Type())
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		if rv.NumMethod() != 0 {
+			return mismatch()
+		}
+
+		rv.Set(reflect.ValueOf(generic(v)))
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return mismatch()
+		}
+
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		i, ok := asInt64(v)
+		if !ok || rv.OverflowInt(i) {
+			return mismatch()
+		}
+
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		u, ok := asUint64(v)
+		if !ok || rv.OverflowUint(u) {
+			return mismatch()
+		}
+
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, ok := v.(float64)
+		if !ok {
+			return mismatch()
+		}
+
+		rv.SetFloat(f)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return mismatch()
+		}
+
+		rv.SetString(s)
+	case reflect.Slice:
+		return assignSlice(v, rv)
+	case reflect.Array:
+		return assignArray(v, rv)
+	case reflect.Struct:
+		s, ok := v.(*section)
+		if !ok {
+			return mismatch()
+		}
+
+		return assignStruct(s, rv)
+	case reflect.Map:
+		s, ok := v.(*section)
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			return mismatch()
+		}
+
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(s.entries)))
+		}
+
+		for _, e := range s.entries {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := assign(e.value, elem); err != nil {
+				return fmt.Errorf("entry '%s': %w", e.name, err)
+			}
+
+			rv.SetMapIndex(reflect.ValueOf(e.name).Convert(rv.Type().Key()), elem)
+		}
+	default:
+		return mismatch()
+	}
+
+	return nil
+}
+
+func assignStruct(s *section, rv reflect.Value) error {
+	fields := cachedFields(rv.Type())
+
+	for _, e := range s.entries {
+		for _, f := range fields {
+			if f.name != e.name {
+				continue
+			}
+
+			if err := assign(e.value, fieldByIndex(rv, f.index)); err != nil {
+				return fmt.Errorf("field '%s': %w", e.name, err)
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func assignSlice(v interface{}, rv reflect.Value) error {
+	switch value := v.(type) {
+	case string:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			rv.SetBytes(append([]byte(nil), value...))
+			return nil
+		}
+
+		return assignBlob(value, rv)
+	case *array:
+		slice := reflect.MakeSlice(rv.Type(), len(value.items), len(value.items))
+		for idx, item := range value.items {
+			if err := assign(item, slice.Index(idx)); err != nil {
+				return fmt.Errorf("index %d: %w", idx, err)
+			}
+		}
+
+		rv.Set(slice)
+		return nil
+	}
+
+	return fmt.Errorf("cannot unmarshal %s into %s", typeName(v), rv.Type())
+}
+
+func assignArray(v interface{}, rv reflect.Value) error {
+	switch value := v.(type) {
+	case string:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			if len(value) != rv.Len() {
+				return fmt.Errorf("cannot unmarshal string of "+
+					"size %d into %s", len(value), rv.Type())
+			}
+
+			reflect.Copy(rv, reflect.ValueOf([]byte(value)))
+			return nil
+		}
+
+		return assignBlob(value, rv)
+	case *array:
+		if len(value.items) != rv.Len() {
+			return fmt.Errorf("cannot unmarshal array of %d "+
+				"elements into %s", len(value.items), rv.Type())
+		}
+
+		for idx, item := range value.items {
+			if err := assign(item, rv.Index(idx)); err != nil {
+				return fmt.Errorf("index %d: %w", idx, err)
+			}
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("cannot unmarshal %s into %s", typeName(v), rv.Type())
+}
+
+// assignBlob decodes a string of packed little-endian fixed-size elements
+// into the slice or array `rv`.
+func assignBlob(blob string, rv reflect.Value) error {
+	elemSize := binary.Size(reflect.Zero(rv.Type().Elem()).Interface())
+	if elemSize <= 0 {
+		return fmt.Errorf("cannot unmarshal string into %s", rv.Type())
+	}
+
+	if len(blob)%elemSize != 0 {
+		return fmt.Errorf("cannot unmarshal blob of size %d into %s: "+
+			"not a multiple of %d", len(blob), rv.Type(), elemSize)
+	}
+
+	count := len(blob) / elemSize
+
+	target := rv
+	if rv.Kind() == reflect.Slice {
+		target = reflect.MakeSlice(rv.Type(), count, count)
+	} else if rv.Len() != count {
+		return fmt.Errorf("cannot unmarshal blob of %d elements "+
+			"into %s", count, rv.Type())
+	}
+
+	err := binary.Read(
+		bytes.NewReader([]byte(blob)), binary.LittleEndian,
+		target.Slice(0, count).Interface(),
+	)
+	if err != nil {
+		return fmt.Errorf("read blob: %w", err)
+	}
+
+	if rv.Kind() == reflect.Slice {
+		rv.Set(target)
+	}
+
+	return nil
+}
+
+func asInt64(v interface{}) (int64, bool) {
+	switch i := v.(type) {
+	case int64:
+		return i, true
+	case int32:
+		return int64(i), true
+	case int16:
+		return int64(i), true
+	case int8:
+		return int64(i), true
+	}
+
+	u, ok := asUint64(v)
+	if !ok || u > math.MaxInt64 {
+		return 0, false
+	}
+
+	return int64(u), true
+}
+
+func asUint64(v interface{}) (uint64, bool) {
+	switch u := v.(type) {
+	case uint64:
+		return u, true
+	case uint32:
+		return uint64(u), true
+	case uint16:
+		return uint64(u), true
+	case uint8:
+		return uint64(u), true
+	}
+
+	var i int64
+
+	switch n := v.(type) {
+	case int64:
+		i = n
+	case int32:
+		i = int64(n)
+	case int16:
+		i = int64(n)
+	case int8:
+		i = int64(n)
+	default:
+		return 0, false
+	}
+
+	if i < 0 {
+		return 0, false
+	}
+
+	return uint64(i), true
+}
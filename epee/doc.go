@@ -0,0 +1,24 @@
+// Package epee implements encoding and decoding of epee's "portable storage"
+// binary format, the serialization used by `monerod` for its `.bin`
+// endpoints and levin p2p messages.
+//
+// Marshalling and unmarshalling are driven by struct tags in a fashion
+// comparable to `encoding/json`:
+//
+//	type GetBlocksByHeightRequest struct {
+//		Heights []uint64 `epee:"heights"`
+//		Prune   bool     `epee:"prune,omitempty"`
+//	}
+//
+// The name of the entry defaults to the name of the field, a name of "-"
+// skips the field altogether, and the following options are supported:
+//
+//   - omitempty: skips the field when encoding if it holds the zero value.
+//
+//   - blob: encodes a slice (or array) of fixed-size elements (integers,
+//     floats or byte arrays) as a single string containing their packed
+//     little-endian representation, like epee's
+//     `KV_SERIALIZE_CONTAINER_POD_AS_BLOB`. When decoding, such string
+//     entries are accepted for any slice of fixed-size elements regardless
+//     of the option.
+package epee
@@ -0,0 +1,414 @@
+package epee
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+var marshalerType = reflect.TypeOf((*Marshaler)(nil)).Elem()
+
+// Marshal encodes `v`, which must be a struct or a map with string keys (or a
+// pointer to one), in the portable storage format.
+func Marshal(v interface{}) ([]byte, error) {
+	e := &encoder{}
+
+	var header [headerSize]byte
+	binary.LittleEndian.PutUint32(header[0:], signatureA)
+	binary.LittleEndian.PutUint32(header[4:], signatureB)
+	header[8] = formatVersion
+
+	e.buf = append(e.buf, header[:]...)
+
+	rv, err := resolve(reflect.ValueOf(v))
+	if err != nil {
+		return nil, err
+	}
+
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("cannot marshal nil value")
+	}
+
+	if err := e.writeSection(rv, 0); err != nil {
+		return nil, err
+	}
+
+	return e.buf, nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+// resolve dereferences pointers and interfaces, giving types implementing
+// Marshaler the chance of providing a replacement value. An invalid value is
+// returned for nil pointers and interfaces.
+func resolve(v reflect.Value) (reflect.Value, error) {
+	for v.IsValid() {
+		if v.Kind() != reflect.Ptr && v.CanAddr() &&
+			reflect.PtrTo(v.Type()).Implements(marshalerType) {
+			v = v.Addr()
+		}
+
+		if v.Type().Implements(marshalerType) {
+			if v.Kind() == reflect.Ptr && v.IsNil() {
+				return reflect.Value{}, nil
+			}
+
+			replacement, err := v.Interface().(Marshaler).MarshalEpee()
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("marshal epee: %w", err)
+			}
+
+			v = reflect.ValueOf(replacement)
+			continue
+		}
+
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if v.IsNil() {
+				return reflect.Value{}, nil
+			}
+
+			v = v.Elem()
+		default:
+			return v, nil
+		}
+	}
+
+	return v, nil
+}
+
+// sectionEntry is an entry to be written to a section.
+type sectionEntry struct {
+	name  string
+	value reflect.Value
+	blob  bool
+}
+
+func (e *encoder) writeSection(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("exceeded max depth of %d", maxDepth)
+	}
+
+	entries := []sectionEntry{}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for _, f := range cachedFields(v.Type()) {
+			fv := fieldByIndex(v, f.index)
+			if f.omitEmpty && fv.IsZero() {
+				continue
+			}
+
+			entries = append(entries, sectionEntry{
+				name: f.name, value: fv, blob: f.blob,
+			})
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s",
+				v.Type().Key())
+		}
+
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+
+		for _, key := range keys {
+			entries = append(entries, sectionEntry{
+				name: key.String(), value: v.MapIndex(key),
+			})
+		}
+	default:
+		return fmt.Errorf("cannot marshal %s as an object", v.Type())
+	}
+
+	// values that can't be represented (nil pointers) are left out, as
+	// are, just like epee does, empty containers held by struct fields.
+	//
+	resolved := entries[:0]
+	for _, entry := range entries {
+		rv, err := resolve(entry.value)
+		if err != nil {
+			return fmt.Errorf("entry '%s': %w", entry.name, err)
+		}
+
+		if !rv.IsValid() || (v.Kind() == reflect.Struct && isEmptyContainer(rv)) {
+			continue
+		}
+
+		entry.value = rv
+		resolved = append(resolved, entry)
+	}
+
+	var err error
+
+	e.buf, err = appendVarint(e.buf, uint64(len(resolved)))
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range resolved {
+		if len(entry.name) > maxNameSize {
+			return fmt.Errorf("entry name '%s' too long", entry.name)
+		}
+
+		e.buf = append(e.buf, byte(len(entry.name)))
+		e.buf = append(e.buf, entry.name...)
+
+		if err := e.writeEntry(entry.value, entry.blob, depth); err != nil {
+			return fmt.Errorf("entry '%s': %w", entry.name, err)
+		}
+	}
+
+	return nil
+}
+
+// isEmptyContainer reports whether `v` is a slice or map with no elements.
+func isEmptyContainer(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+
+	return false
+}
+
+// writeEntry writes the type of `v` followed by its value.
+func (e *encoder) writeEntry(v reflect.Value, blob bool, depth int) error {
+	if blob {
+		e.buf = append(e.buf, TypeString)
+		return e.writeBlob(v)
+	}
+
+	typ, err := valueType(v)
+	if err != nil {
+		return err
+	}
+
+	if typ == TypeArray {
+		return e.writeArray(v, depth)
+	}
+
+	e.buf = append(e.buf, typ)
+
+	return e.writeValue(v, typ, depth)
+}
+
+// valueType determines the type of the entry that `v` (already resolved)
+// should be encoded as.
+func valueType(v reflect.Value) (byte, error) {
+	switch v.Kind() {
+	case reflect.Bool:
+		return TypeBool, nil
+	case reflect.Int8:
+		return TypeInt8, nil
+	case reflect.Int16:
+		return TypeInt16, nil
+	case reflect.Int32:
+		return TypeInt32, nil
+	case reflect.Int, reflect.Int64:
+		return TypeInt64, nil
+	case reflect.Uint8:
+		return TypeUint8, nil
+	case reflect.Uint16:
+		return TypeUint16, nil
+	case reflect.Uint32:
+		return TypeUint32, nil
+	case reflect.Uint, reflect.Uint64:
+		return TypeUint64, nil
+	case reflect.Float32, reflect.Float64:
+		return TypeDouble, nil
+	case reflect.String:
+		return TypeString, nil
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return TypeString, nil
+		}
+
+		return TypeArray, nil
+	case reflect.Struct, reflect.Map:
+		return TypeObject, nil
+	}
+
+	return 0, fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// writeValue writes the value `v` (already resolved) of type `typ`.
+func (e *encoder) writeValue(v reflect.Value, typ byte, depth int) error {
+	var err error
+
+	switch typ {
+	case TypeBool:
+		if v.Bool() {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case TypeInt8:
+		e.buf = append(e.buf, byte(v.Int()))
+	case TypeInt16:
+		e.buf = appendUint16(e.buf, uint16(v.Int()))
+	case TypeInt32:
+		e.buf = appendUint32(e.buf, uint32(v.Int()))
+	case TypeInt64:
+		e.buf = appendUint64(e.buf, uint64(v.Int()))
+	case TypeUint8:
+		e.buf = append(e.buf, byte(v.Uint()))
+	case TypeUint16:
+		e.buf = appendUint16(e.buf, uint16(v.Uint()))
+	case TypeUint32:
+		e.buf = appendUint32(e.buf, uint32(v.Uint()))
+	case TypeUint64:
+		e.buf = appendUint64(e.buf, v.Uint())
+	case TypeDouble:
+		e.buf = appendUint64(e.buf, math.Float64bits(v.Float()))
+	case TypeString:
+		var b []byte
+
+		switch v.Kind() {
+		case reflect.String:
+			b = []byte(v.String())
+		case reflect.Slice:
+			b = v.Bytes()
+		case reflect.Array:
+			b = make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+		}
+
+		e.buf, err = appendVarint(e.buf, uint64(len(b)))
+		if err != nil {
+			return err
+		}
+
+		e.buf = append(e.buf, b...)
+	case TypeObject:
+		return e.writeSection(v, depth+1)
+	default:
+		return fmt.Errorf("unsupported entry type %d", typ)
+	}
+
+	return nil
+}
+
+// writeArray writes the array type, the number of elements and the elements
+// of `v` (a slice or array) themselves.
+func (e *encoder) writeArray(v reflect.Value, depth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("exceeded max depth of %d", maxDepth)
+	}
+
+	elems := make([]reflect.Value, v.Len())
+	elemType := byte(0)
+
+	for idx := range elems {
+		elem, err := resolve(v.Index(idx))
+		if err != nil {
+			return fmt.Errorf("index %d: %w", idx, err)
+		}
+
+		if !elem.IsValid() {
+			return fmt.Errorf("index %d: cannot marshal nil element", idx)
+		}
+
+		typ, err := valueType(elem)
+		if err != nil {
+			return fmt.Errorf("index %d: %w", idx, err)
+		}
+
+		if idx == 0 {
+			elemType = typ
+		} else if typ != elemType {
+			return fmt.Errorf("index %d: mixed types in array (%d and %d)",
+				idx, elemType, typ)
+		}
+
+		elems[idx] = elem
+	}
+
+	if len(elems) == 0 {
+		// the type is irrelevant as there are no values, but try to
+		// stick to the one of the elements anyway.
+		//
+		t, err := valueType(reflect.Zero(v.Type().Elem()))
+		if err != nil {
+			elemType = TypeString
+		} else {
+			elemType = t
+		}
+	}
+
+	e.buf = append(e.buf, FlagArray|elemType)
+
+	var err error
+
+	e.buf, err = appendVarint(e.buf, uint64(len(elems)))
+	if err != nil {
+		return err
+	}
+
+	for idx, elem := range elems {
+		if elemType == TypeArray {
+			err = e.writeArray(elem, depth+1)
+		} else {
+			err = e.writeValue(elem, elemType, depth)
+		}
+
+		if err != nil {
+			return fmt.Errorf("index %d: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// writeBlob writes the elements of `v` packed together as a single string.
+func (e *encoder) writeBlob(v reflect.Value) error {
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("cannot marshal %s as a blob", v.Type())
+	}
+
+	if binary.Size(reflect.Zero(v.Type().Elem()).Interface()) <= 0 {
+		return fmt.Errorf("cannot marshal %s as a blob: elements "+
+			"not fixed-size", v.Type())
+	}
+
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.LittleEndian, v.Interface()); err != nil {
+		return fmt.Errorf("write blob: %w", err)
+	}
+
+	var err error
+
+	e.buf, err = appendVarint(e.buf, uint64(buf.Len()))
+	if err != nil {
+		return err
+	}
+
+	e.buf = append(e.buf, buf.Bytes()...)
+
+	return nil
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.LittleEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
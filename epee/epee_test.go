@@ -0,0 +1,327 @@
+package epee_test
+
+import (
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/epee"
+)
+
+// header is the signature and version every payload starts with.
+const header = "011101010101020101"
+
+// payloads holds hand-assembled portable storage payloads, annotated
+// byte-by-byte, shared by the unit and fuzz tests.
+var payloads = map[string]string{
+	// {heights: [1, 2]}, as sent to `/get_blocks_by_height.bin`.
+	//
+	"get_blocks_by_height": header +
+		"04" + // 1 entry
+		"07" + hex.EncodeToString([]byte("heights")) +
+		"85" + // array of uint64
+		"08" + // 2 elements
+		"0100000000000000" +
+		"0200000000000000",
+
+	// {blocks: [{block: "\x01\x02", txs: ["\xaa"]}], start_height: 100,
+	// status: "OK", untrusted: false}, shaped like a reply from
+	// `/get_blocks.bin`.
+	//
+	"get_blocks": header +
+		"10" + // 4 entries
+		"06" + hex.EncodeToString([]byte("blocks")) +
+		"8c" + // array of objects
+		"04" + // 1 element
+		"08" + // 2 entries
+		"05" + hex.EncodeToString([]byte("block")) +
+		"0a" + "08" + "0102" + // string of 2 bytes
+		"03" + hex.EncodeToString([]byte("txs")) +
+		"8a" + "04" + "04" + "aa" + // array w/ 1 string of 1 byte
+		"0c" + hex.EncodeToString([]byte("start_height")) +
+		"05" + "6400000000000000" +
+		"06" + hex.EncodeToString([]byte("status")) +
+		"0a" + "08" + hex.EncodeToString([]byte("OK")) +
+		"09" + hex.EncodeToString([]byte("untrusted")) +
+		"0b" + "00",
+
+	// {block_ids: <two 32-byte hashes packed as a blob>}.
+	//
+	"block_ids": header +
+		"04" + // 1 entry
+		"09" + hex.EncodeToString([]byte("block_ids")) +
+		"0a" + "0101" + // string of 64 bytes (2-byte varint)
+		strings.Repeat("11", 32) +
+		strings.Repeat("22", 32),
+}
+
+func payload(t testing.TB, name string) []byte {
+	b, err := hex.DecodeString(payloads[name])
+	require.NoError(t, err)
+
+	return b
+}
+
+// captures gives the replies captured from `monerod` found under
+// testdata/captures, keyed by file name, each of which must have its origin
+// recorded in testdata/captures/SOURCES (see the README there).
+func captures(t testing.TB) map[string][]byte {
+	files, err := filepath.Glob(filepath.Join("testdata", "captures", "*.bin"))
+	require.NoError(t, err)
+
+	sources, err := os.ReadFile(filepath.Join("testdata", "captures", "SOURCES"))
+	require.NoError(t, err)
+
+	res := map[string][]byte{}
+
+	for _, file := range files {
+		name := filepath.Base(file)
+		require.Regexp(t, `(?m)^`+regexp.QuoteMeta(name)+`\s`, string(sources),
+			"origin of %s not recorded in SOURCES", name)
+
+		b, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		res[name] = b
+	}
+
+	return res
+}
+
+type getBlocksByHeightRequest struct {
+	Heights []uint64 `epee:"heights"`
+	Prune   bool     `epee:"prune,omitempty"`
+}
+
+type footer struct {
+	Status    string `epee:"status"`
+	Untrusted bool   `epee:"untrusted"`
+}
+
+type block struct {
+	Block string   `epee:"block"`
+	Txs   []string `epee:"txs"`
+}
+
+type getBlocksResponse struct {
+	Blocks      []block `epee:"blocks"`
+	StartHeight uint32  `epee:"start_height"`
+
+	footer
+}
+
+type blockIDs struct {
+	BlockIDs [][32]byte `epee:"block_ids,blob"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	t.Run("get_blocks_by_height", func(t *testing.T) {
+		t.Parallel()
+
+		v := &getBlocksByHeightRequest{}
+		require.NoError(t, epee.Unmarshal(payload(t, "get_blocks_by_height"), v))
+		assert.Equal(t, []uint64{1, 2}, v.Heights)
+	})
+
+	t.Run("get_blocks", func(t *testing.T) {
+		t.Parallel()
+
+		v := &getBlocksResponse{}
+		require.NoError(t, epee.Unmarshal(payload(t, "get_blocks"), v))
+
+		assert.Equal(t, &getBlocksResponse{
+			Blocks: []block{
+				{Block: "\x01\x02", Txs: []string{"\xaa"}},
+			},
+			StartHeight: 100,
+			footer:      footer{Status: "OK"},
+		}, v)
+	})
+
+	t.Run("block_ids", func(t *testing.T) {
+		t.Parallel()
+
+		v := &blockIDs{}
+		require.NoError(t, epee.Unmarshal(payload(t, "block_ids"), v))
+		require.Len(t, v.BlockIDs, 2)
+		assert.Equal(t, byte(0x11), v.BlockIDs[0][31])
+		assert.Equal(t, byte(0x22), v.BlockIDs[1][0])
+	})
+
+	t.Run("generic", func(t *testing.T) {
+		t.Parallel()
+
+		var v interface{}
+		require.NoError(t, epee.Unmarshal(payload(t, "get_blocks"), &v))
+
+		assert.Equal(t, map[string]interface{}{
+			"blocks": []interface{}{
+				map[string]interface{}{
+					"block": "\x01\x02",
+					"txs":   []interface{}{"\xaa"},
+				},
+			},
+			"start_height": uint64(100),
+			"status":       "OK",
+			"untrusted":    false,
+		}, v)
+	})
+}
+
+func TestUnmarshalCaptures(t *testing.T) {
+	t.Parallel()
+
+	replies := captures(t)
+	if len(replies) == 0 {
+		t.Skip("no captures under testdata/captures")
+	}
+
+	for name, b := range replies {
+		b := b
+
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var v interface{}
+			require.NoError(t, epee.Unmarshal(b, &v))
+
+			fields, ok := v.(map[string]interface{})
+			require.True(t, ok)
+			assert.Equal(t, "OK", fields["status"])
+
+			// what monerod sends must survive a round trip.
+			//
+			out, err := epee.Marshal(v)
+			require.NoError(t, err)
+
+			var again interface{}
+			require.NoError(t, epee.Unmarshal(out, &again))
+
+			out2, err := epee.Marshal(again)
+			require.NoError(t, err)
+			assert.Equal(t, out, out2)
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "empty",
+			input: "",
+			err:   "unexpected end",
+		},
+
+		{
+			name:  "bad signature",
+			input: "011101010101020201" + "00",
+			err:   "signature",
+		},
+
+		{
+			name:  "truncated",
+			input: payloads["get_blocks_by_height"][:40],
+			err:   "unexpected end",
+		},
+
+		{
+			name:  "overflowing count",
+			input: header + "fdffffff",
+			err:   "exceeds remaining input",
+		},
+
+		{
+			name:  "type mismatch",
+			input: header + "04" + "07" + hex.EncodeToString([]byte("heights")) + "0b" + "01",
+			err:   "cannot unmarshal bool",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := hex.DecodeString(tc.input)
+			require.NoError(t, err)
+
+			err = epee.Unmarshal(input, &getBlocksByHeightRequest{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	b, err := epee.Marshal(&getBlocksByHeightRequest{Heights: []uint64{1, 2}})
+	require.NoError(t, err)
+	assert.Equal(t, payloads["get_blocks_by_height"], hex.EncodeToString(b))
+
+	b, err = epee.Marshal(&getBlocksResponse{
+		Blocks:      []block{{Block: "\x01\x02", Txs: []string{"\xaa"}}},
+		StartHeight: 100,
+		footer:      footer{Status: "OK"},
+	})
+	require.NoError(t, err)
+
+	// start_height is encoded as an uint32 given the type of the field,
+	// but otherwise should match the captured payload byte by byte.
+	//
+	expected := strings.Replace(payloads["get_blocks"],
+		"05"+"6400000000000000", "06"+"64000000", 1)
+	assert.Equal(t, expected, hex.EncodeToString(b))
+
+	ids := &blockIDs{BlockIDs: make([][32]byte, 2)}
+	for i := range ids.BlockIDs[0] {
+		ids.BlockIDs[0][i], ids.BlockIDs[1][i] = 0x11, 0x22
+	}
+
+	b, err = epee.Marshal(ids)
+	require.NoError(t, err)
+	assert.Equal(t, payloads["block_ids"], hex.EncodeToString(b))
+}
+
+func TestVarint(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value uint64
+		size  int
+	}{
+		{0, 1},
+		{63, 1},
+		{64, 2},
+		{16383, 2},
+		{16384, 4},
+		{1<<30 - 1, 4},
+		{1 << 30, 8},
+		{1<<62 - 1, 8},
+	} {
+		b, err := epee.AppendVarint(nil, tc.value)
+		require.NoError(t, err)
+		assert.Len(t, b, tc.size)
+
+		value, n, err := epee.ReadVarint(b)
+		require.NoError(t, err)
+		assert.Equal(t, tc.size, n)
+		assert.Equal(t, tc.value, value)
+	}
+
+	_, err := epee.AppendVarint(nil, 1<<62)
+	assert.Error(t, err)
+}
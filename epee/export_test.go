@@ -0,0 +1,6 @@
+package epee
+
+var (
+	AppendVarint = appendVarint
+	ReadVarint   = readVarint
+)
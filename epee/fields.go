@@ -0,0 +1,131 @@
+package epee
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// field describes how a struct field maps to an entry of a section.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	blob      bool
+}
+
+// fieldCache caches the fields of each struct type seen.
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// cachedFields retrieves the list of fields that map to entries for the
+// struct type `t`.
+func cachedFields(t reflect.Type) []field {
+	if f, ok := fieldCache.Load(t); ok {
+		return f.([]field)
+	}
+
+	f, _ := fieldCache.LoadOrStore(t, typeFields(t))
+	return f.([]field)
+}
+
+// typeFields gathers the fields of the struct type `t`, inlining the fields
+// of untagged embedded structs. In case of conflicting names, the least
+// nested field wins.
+func typeFields(t reflect.Type) []field {
+	type candidate struct {
+		field
+		depth int
+	}
+
+	candidates := []candidate{}
+
+	var walk func(t reflect.Type, index []int, depth int)
+	walk = func(t reflect.Type, index []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+
+			tag, tagged := sf.Tag.Lookup("epee")
+			if tag == "-" {
+				continue
+			}
+
+			fieldIndex := make([]int, len(index)+1)
+			copy(fieldIndex, index)
+			fieldIndex[len(index)] = i
+
+			if sf.Anonymous && !tagged && sf.Type.Kind() == reflect.Struct {
+				walk(sf.Type, fieldIndex, depth+1)
+				continue
+			}
+
+			if sf.PkgPath != "" { // unexported
+				continue
+			}
+
+			name, opts := parseTag(tag)
+			if name == "" {
+				name = sf.Name
+			}
+
+			candidates = append(candidates, candidate{
+				field: field{
+					name:      name,
+					index:     fieldIndex,
+					omitEmpty: opts.contains("omitempty"),
+					blob:      opts.contains("blob"),
+				},
+				depth: depth,
+			})
+		}
+	}
+
+	walk(t, nil, 0)
+
+	best := map[string]int{}
+	for idx, c := range candidates {
+		prev, found := best[c.name]
+		if !found || c.depth < candidates[prev].depth {
+			best[c.name] = idx
+		}
+	}
+
+	fields := []field{}
+	for idx, c := range candidates {
+		if best[c.name] == idx {
+			fields = append(fields, c.field)
+		}
+	}
+
+	return fields
+}
+
+// tagOptions is the comma-separated list of options that follows the name in
+// a struct tag.
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tagOptions(tag[idx+1:])
+	}
+
+	return tag, ""
+}
+
+func (o tagOptions) contains(option string) bool {
+	for _, opt := range strings.Split(string(o), ",") {
+		if opt == option {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fieldByIndex retrieves the (possibly nested) field of `v` at `index`.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		v = v.Field(i)
+	}
+
+	return v
+}
@@ -0,0 +1,54 @@
+package epee_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/epee"
+)
+
+func FuzzUnmarshal(f *testing.F) {
+	for name := range payloads {
+		f.Add(payload(f, name))
+	}
+
+	for _, b := range captures(f) {
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var generic interface{}
+		if err := epee.Unmarshal(data, &generic); err != nil {
+			return
+		}
+
+		// whatever decodes generically must survive a round trip.
+		//
+		b, err := epee.Marshal(generic)
+		require.NoError(t, err)
+
+		var again interface{}
+		require.NoError(t, epee.Unmarshal(b, &again))
+
+		b2, err := epee.Marshal(again)
+		require.NoError(t, err)
+		require.Equal(t, b, b2)
+	})
+}
+
+func FuzzUnmarshalStruct(f *testing.F) {
+	for name := range payloads {
+		f.Add(payload(f, name))
+	}
+
+	for _, b := range captures(f) {
+		f.Add(b)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_ = epee.Unmarshal(data, &getBlocksResponse{})
+		_ = epee.Unmarshal(data, &getBlocksByHeightRequest{})
+		_ = epee.Unmarshal(data, &blockIDs{})
+	})
+}
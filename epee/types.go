@@ -0,0 +1,68 @@
+package epee
+
+const (
+	// signatureA and signatureB are the two little-endian 32-bit values
+	// that every portable storage payload starts with.
+	signatureA uint32 = 0x01011101
+	signatureB uint32 = 0x01020101
+
+	// formatVersion is the version of the format that follows the
+	// signatures.
+	formatVersion byte = 1
+
+	// headerSize is the total size of the header: both signatures plus
+	// the version.
+	headerSize = 4 + 4 + 1
+)
+
+// Types of the values of the entries.
+const (
+	TypeInt64  byte = 1
+	TypeInt32  byte = 2
+	TypeInt16  byte = 3
+	TypeInt8   byte = 4
+	TypeUint64 byte = 5
+	TypeUint32 byte = 6
+	TypeUint16 byte = 7
+	TypeUint8  byte = 8
+	TypeDouble byte = 9
+	TypeString byte = 10
+	TypeBool   byte = 11
+	TypeObject byte = 12
+	TypeArray  byte = 13
+
+	// FlagArray is OR'ed with the type of the elements of an array to
+	// form the type of the array entry.
+	FlagArray byte = 0x80
+)
+
+const (
+	// maxDepth is the maximum level of nesting of objects and arrays
+	// accepted when decoding.
+	maxDepth = 100
+
+	// maxNameSize is the maximum size of the name of an entry, as it's
+	// prefixed by a single byte.
+	maxNameSize = 255
+)
+
+// Marshaler is implemented by types that can provide a different value to be
+// encoded in their place.
+type Marshaler interface {
+	MarshalEpee() (interface{}, error)
+}
+
+// Unmarshaler is implemented by types that can decode themselves from the
+// generic representation of a value, i.e., what would be obtained by
+// unmarshalling it into an `interface{}`:
+//
+//   - objects are `map[string]interface{}`
+//   - arrays are `[]interface{}`
+//   - strings are `string` (note: possibly holding binary data)
+//   - integers are `int64`, `int32`, `int16`, `int8`, `uint64`,
+//     `uint32`, `uint16` or `uint8`, depending on how they were encoded
+//   - doubles are `float64`
+//   - booleans are `bool`
+type Unmarshaler interface {
+	UnmarshalEpee(v interface{}) error
+}
@@ -0,0 +1,66 @@
+package epee
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// varint sizes are encoded in the two least significant bits of the
+	// first byte.
+	varintSizeMask = 0x03
+
+	varintMax1 = 1<<6 - 1
+	varintMax2 = 1<<14 - 1
+	varintMax4 = 1<<30 - 1
+	varintMax8 = 1<<62 - 1
+)
+
+// appendVarint appends `v` encoded as an epee varint to `b`.
+func appendVarint(b []byte, v uint64) ([]byte, error) {
+	switch {
+	case v <= varintMax1:
+		return append(b, byte(v<<2)), nil
+	case v <= varintMax2:
+		return append(b, byte(v<<2|1), byte(v>>6)), nil
+	case v <= varintMax4:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(v<<2|2))
+		return append(b, buf[:]...), nil
+	case v <= varintMax8:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], v<<2|3)
+		return append(b, buf[:]...), nil
+	}
+
+	return nil, fmt.Errorf("varint: %d exceeds maximum of %d", v, uint64(varintMax8))
+}
+
+// readVarint reads an epee varint from the beginning of `b`, returning the
+// value and the number of bytes consumed.
+func readVarint(b []byte) (uint64, int, error) {
+	if len(b) == 0 {
+		return 0, 0, fmt.Errorf("varint: %w", errUnexpectedEOF)
+	}
+
+	size := 1 << (b[0] & varintSizeMask)
+	if len(b) < size {
+		return 0, 0, fmt.Errorf("varint: need %d bytes, have %d: %w",
+			size, len(b), errUnexpectedEOF)
+	}
+
+	var v uint64
+
+	switch size {
+	case 1:
+		v = uint64(b[0])
+	case 2:
+		v = uint64(binary.LittleEndian.Uint16(b))
+	case 4:
+		v = uint64(binary.LittleEndian.Uint32(b))
+	case 8:
+		v = binary.LittleEndian.Uint64(b)
+	}
+
+	return v >> 2, size, nil
+}
@@ -35,11 +35,41 @@ type ClientConfig struct {
 	//
 	TLSCACert string
 
+	// TLSFingerprints restricts the certificates trusted to those whose
+	// SHA-256 fingerprint (hex or colon-separated) is listed, just like
+	// monerod's `--rpc-ssl-allowed-fingerprints` (see
+	// `WithPinnedFingerprints`).
+	//
+	// ps.: certificates chaining up to TLSCACert, if set, are trusted
+	// too.
+	//
+	TLSFingerprints []string
+
+	// TLSFingerprintStore enables trusting on first use the certificate of
+	// each server, persisting its fingerprint to the store (see
+	// `WithTrustOnFirstUse`).
+	//
+	// ps.: can't be combined with TLSFingerprints.
+	//
+	TLSFingerprintStore FingerprintStore
+
+	// TLSReloader, if set, supplies the client certificate and certificate
+	// authorities for every new connection, taking precedence over
+	// TLSClientCert, TLSClientKey and TLSCACert.
+	//
+	TLSReloader *TLSReloader
+
 	// Verbose dictates whether the transport should dump all request and
-	// response information to stderr.
+	// response information (to stderr by default, with secrets redacted).
 	//
 	Verbose bool
 
+	// DumpOptions tweaks how requests and responses are dumped when
+	// Verbose is set (e.g., where to, or in which format - see
+	// `NewDumpTransport`).
+	//
+	DumpOptions []DumpOption
+
 	// RequestTimeout places a deadline on every request issued by this
 	// client.
 	//
@@ -61,6 +91,32 @@ type ClientConfig struct {
 	// body of every request and response will still be cleartext.
 	//
 	Password string
+
+	// Proxy is the address of a SOCKS5 proxy (e.g., Tor's or I2P's) that
+	// all connections go through, either as `host:port` or as a
+	// `socks5://[user:password@]host:port` URL.
+	//
+	// ps.: without a proxy, connections to .onion and .i2p hosts are
+	// refused (see `ErrHiddenServiceWithoutProxy`).
+	//
+	Proxy string
+
+	// ProxyIsolation makes the client authenticate to the proxy with
+	// random credentials of its own, so that Tor isolates its streams
+	// from those of other clients.
+	//
+	// ps.: can't be combined with credentials in `Proxy`.
+	//
+	ProxyIsolation bool
+
+	// UnixSocket is the path to a unix domain socket that all connections
+	// are made to, regardless of the host in the URL of a request (e.g.,
+	// of a local reverse proxy in front of `monerod`).
+	//
+	// ps.: TLS, if used, verifies the certificate against the host in the
+	// URL.
+	//
+	UnixSocket string
 }
 
 func (c ClientConfig) Validate() error {
@@ -74,6 +130,23 @@ func (c ClientConfig) Validate() error {
 			"tls client key")
 	}
 
+	if c.TLSSkipVerify &&
+		(len(c.TLSFingerprints) != 0 || c.TLSFingerprintStore != nil) {
+		return fmt.Errorf("tls skip verify specified together " +
+			"with fingerprint verification")
+	}
+
+	if len(c.TLSFingerprints) != 0 && c.TLSFingerprintStore != nil {
+		return fmt.Errorf("tls fingerprints specified together " +
+			"with tls fingerprint store")
+	}
+
+	for _, fp := range c.TLSFingerprints {
+		if _, err := ParseFingerprint(fp); err != nil {
+			return fmt.Errorf("tls fingerprints: %w", err)
+		}
+	}
+
 	if c.Username != "" && c.Password == "" {
 		return fmt.Errorf("username specified but password not")
 	}
@@ -82,9 +155,71 @@ func (c ClientConfig) Validate() error {
 		return fmt.Errorf("password specified but username not")
 	}
 
+	if err := c.validateProxy(); err != nil {
+		return fmt.Errorf("proxy: %w", err)
+	}
+
+	return nil
+}
+
+func (c ClientConfig) validateProxy() error {
+	if c.UnixSocket != "" && c.Proxy != "" {
+		return fmt.Errorf("unix socket specified together with proxy")
+	}
+
+	if c.Proxy == "" {
+		if c.ProxyIsolation {
+			return fmt.Errorf("proxy isolation specified but proxy not")
+		}
+
+		return nil
+	}
+
+	proxyURL, err := parseProxy(c.Proxy)
+	if err != nil {
+		return fmt.Errorf("parse: %w", err)
+	}
+
+	if c.ProxyIsolation && proxyURL.User != nil {
+		return fmt.Errorf("proxy isolation specified together " +
+			"with proxy credentials")
+	}
+
 	return nil
 }
 
+// tlsAdjustments gives the changes to make to the TLS configuration for
+// each connection, if any: swapping in the latest material from the
+// reloader, and verifying the certificate against it.
+//
+func (c ClientConfig) tlsAdjustments() []func(*tls.Config, string) error {
+	var adjust []func(*tls.Config, string) error
+
+	if c.TLSReloader != nil {
+		adjust = append(adjust, func(config *tls.Config, _ string) error {
+			c.TLSReloader.Apply(config)
+			return nil
+		})
+
+		// verification is bound to the certificate authorities
+		// of the configuration it's set up on.
+		//
+		if len(c.TLSFingerprints) != 0 {
+			adjust = append(adjust, func(config *tls.Config, _ string) error {
+				return WithPinnedFingerprints(c.TLSFingerprints...)(config)
+			})
+		}
+	}
+
+	if c.TLSFingerprintStore != nil {
+		adjust = append(adjust, func(config *tls.Config, address string) error {
+			return WithTrustOnFirstUse(c.TLSFingerprintStore, address)(config)
+		})
+	}
+
+	return adjust
+}
+
 // NewClient instantiates a new `http.Client` based on the client configuration
 // supplied.
 //
@@ -110,20 +245,49 @@ func NewClient(cfg ClientConfig) (*http.Client, error) {
 		}
 	}
 
+	if len(cfg.TLSFingerprints) != 0 {
+		err := WithPinnedFingerprints(cfg.TLSFingerprints...)(tlsConfig)
+		if err != nil {
+			return nil, fmt.Errorf("with pinned fingerprints: %w", err)
+		}
+	}
+
 	if cfg.TLSSkipVerify {
 		WithInsecureSkipVerify()(tlsConfig)
 	}
 
+	dialer, err := NewDialer(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("new dialer: %w", err)
+	}
+
 	transport := http.DefaultTransport.(*http.Transport).Clone()
 	transport.TLSClientConfig = tlsConfig
+	transport.DialContext = dialer.DialContext
+
+	if adjust := cfg.tlsAdjustments(); len(adjust) != 0 {
+		transport.DialTLSContext = dialTLS(dialer, tlsConfig, adjust...)
+	}
 
 	client := &http.Client{
 		Timeout:   cfg.RequestTimeout,
 		Transport: transport,
 	}
 
+	if cfg.Proxy != "" {
+		// never let the environment route requests elsewhere.
+		//
+		transport.Proxy = nil
+
+		if client.Timeout == 0 {
+			client.Timeout = DefaultProxiedRequestTimeout
+		}
+	}
+
 	if cfg.Verbose {
-		client.Transport = NewDumpTransport(client.Transport)
+		client.Transport = NewDumpTransport(
+			client.Transport, cfg.DumpOptions...,
+		)
 	}
 
 	if cfg.Username != "" {
@@ -0,0 +1,201 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultDialTimeout is the time allowed for establishing a
+	// connection to a clearnet host (or to the proxy).
+	//
+	DefaultDialTimeout = 30 * time.Second
+
+	// DefaultHiddenServiceDialTimeout is the time allowed for establishing
+	// a connection to a hidden service (.onion or .i2p) through the proxy,
+	// accounting for the time needed to build circuits / tunnels.
+	//
+	DefaultHiddenServiceDialTimeout = 2 * time.Minute
+
+	// DefaultProxiedRequestTimeout is the deadline placed on every request
+	// made by a client going through a proxy when `RequestTimeout` is not
+	// set.
+	//
+	DefaultProxiedRequestTimeout = 5 * time.Minute
+)
+
+// ErrHiddenServiceWithoutProxy is the error returned when trying to reach a
+// .onion or .i2p host without a proxy configured, which would otherwise leak
+// the lookup to the system's resolver.
+//
+var ErrHiddenServiceWithoutProxy = errors.New(
+	"hidden service requires a proxy",
+)
+
+// noDeadline clears a deadline on a connection.
+//
+var noDeadline time.Time
+
+// Dialer establishes connections, as used by the clients in this module for
+// reaching servers (e.g., the HTTP client from `NewClient` or
+// `zmq.Client` via `zmq.WithDialer`).
+//
+type Dialer interface {
+	DialContext(ctx context.Context, network, address string) (
+		net.Conn, error,
+	)
+}
+
+// IsHiddenService tells whether `host` (optionally with a port) is a Tor
+// onion service or an I2P eepsite.
+//
+func IsHiddenService(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	return strings.HasSuffix(host, ".onion") ||
+		strings.HasSuffix(host, ".i2p")
+}
+
+// NewDialer instantiates the dialer configured by `cfg`: one connecting to
+// the unix domain socket in `cfg.UnixSocket`, one going through the SOCKS5
+// proxy in `cfg.Proxy`, or one connecting directly otherwise, in which case
+// hidden services are refused.
+//
+// With `cfg.ProxyIsolation`, every dialer authenticates to the proxy with
+// its own random credentials, which Tor takes as a request for isolating
+// its streams from those of any other dialer (i.e., not sharing circuits).
+//
+func NewDialer(cfg ClientConfig) (Dialer, error) {
+	if err := cfg.validateProxy(); err != nil {
+		return nil, fmt.Errorf("validate proxy: %w", err)
+	}
+
+	forward := &net.Dialer{
+		Timeout:   DefaultDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+
+	if cfg.UnixSocket != "" {
+		return &dialer{forward: forward, unixSocket: cfg.UnixSocket}, nil
+	}
+
+	if cfg.Proxy == "" {
+		return &dialer{forward: forward}, nil
+	}
+
+	proxyURL, err := parseProxy(cfg.Proxy)
+	if err != nil {
+		return nil, fmt.Errorf("parse proxy: %w", err)
+	}
+
+	socks := &socks5Dialer{
+		proxy:   proxyURL.Host,
+		forward: forward,
+	}
+
+	if proxyURL.User != nil {
+		socks.username = proxyURL.User.Username()
+		socks.password, _ = proxyURL.User.Password()
+	}
+
+	if cfg.ProxyIsolation {
+		socks.username, err = randomCredential()
+		if err != nil {
+			return nil, fmt.Errorf("random username: %w", err)
+		}
+
+		socks.password, err = randomCredential()
+		if err != nil {
+			return nil, fmt.Errorf("random password: %w", err)
+		}
+	}
+
+	return &dialer{forward: forward, proxy: socks}, nil
+}
+
+// dialer implements Dialer, applying the policies for hidden services.
+//
+type dialer struct {
+	forward    *net.Dialer
+	proxy      *socks5Dialer
+	unixSocket string
+}
+
+// DialContext connects to the unix domain socket, if any, or to `address`
+// through the proxy, if any, or directly.
+//
+func (d *dialer) DialContext(
+	ctx context.Context, network, address string,
+) (net.Conn, error) {
+	if d.unixSocket != "" {
+		return d.forward.DialContext(ctx, "unix", d.unixSocket)
+	}
+
+	hidden := IsHiddenService(address)
+
+	if d.proxy == nil {
+		if hidden {
+			return nil, fmt.Errorf("dial '%s': %w",
+				address, ErrHiddenServiceWithoutProxy)
+		}
+
+		return d.forward.DialContext(ctx, network, address)
+	}
+
+	timeout := DefaultDialTimeout
+	if hidden {
+		timeout = DefaultHiddenServiceDialTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return d.proxy.DialContext(ctx, network, address)
+}
+
+// parseProxy parses the address of a SOCKS5 proxy, either a plain
+// `host:port` or a `socks5://` (or `socks5h://`) URL.
+//
+func parseProxy(proxy string) (*url.URL, error) {
+	if !strings.Contains(proxy, "://") {
+		proxy = "socks5://" + proxy
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("url parse: %w", err)
+	}
+
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported proxy scheme '%s'", u.Scheme)
+	}
+
+	if u.Port() == "" {
+		return nil, fmt.Errorf("proxy '%s' has no port", u.Host)
+	}
+
+	return u, nil
+}
+
+// randomCredential generates a random SOCKS5 username or password.
+//
+func randomCredential() (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", fmt.Errorf("read full: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
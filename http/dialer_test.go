@@ -0,0 +1,194 @@
+package http_test
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/duggavo/go-monero/http"
+)
+
+// socks5Server is a minimal SOCKS5 proxy, accepting username/password
+// authentication and connecting every request to `target` regardless of the
+// host asked for.
+type socks5Server struct {
+	target string
+
+	mu    sync.Mutex
+	users []string
+	hosts []string
+}
+
+func (s *socks5Server) serve(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go s.handle(conn)
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func (s *socks5Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	// greeting: version, number of methods, methods.
+	b := make([]byte, 3)
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return
+	}
+
+	_, _ = conn.Write([]byte{0x05, b[2]})
+
+	var user string
+	if b[2] == 0x02 {
+		user = readString(conn, 1)
+		_ = readString(conn, 0)
+		_, _ = conn.Write([]byte{0x01, 0x00})
+	}
+
+	// connect: version, command, reserved, address type.
+	b = make([]byte, 4)
+	if _, err := io.ReadFull(conn, b); err != nil {
+		return
+	}
+
+	host := readString(conn, 0)
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.users = append(s.users, user)
+	s.hosts = append(s.hosts, net.JoinHostPort(host,
+		strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+	s.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", s.target)
+	if err != nil {
+		_, _ = conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	_, _ = conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 127, 0, 0, 1, 0, 0})
+
+	go func() { _, _ = io.Copy(upstream, conn) }()
+	_, _ = io.Copy(conn, upstream)
+}
+
+// readString reads a length-prefixed string, skipping `skip` bytes first.
+func readString(r io.Reader, skip int) string {
+	b := make([]byte, skip+1)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return ""
+	}
+
+	s := make([]byte, b[skip])
+	if _, err := io.ReadFull(r, s); err != nil {
+		return ""
+	}
+
+	return string(s)
+}
+
+func TestProxy(t *testing.T) {
+	t.Parallel()
+
+	upstream := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.Host))
+		},
+	))
+	t.Cleanup(upstream.Close)
+
+	proxy := &socks5Server{target: upstream.Listener.Addr().String()}
+	proxyAddr := proxy.serve(t)
+
+	get := func(client *http.Client) string {
+		resp, err := client.Get("http://abcdefghijklmnop.onion:18089/")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		return string(body)
+	}
+
+	for i := 0; i < 2; i++ {
+		client, err := mhttp.NewClient(mhttp.ClientConfig{
+			Proxy:          "socks5://" + proxyAddr,
+			ProxyIsolation: true,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "abcdefghijklmnop.onion:18089", get(client))
+	}
+
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+
+	// the host is resolved by the proxy, and each client is isolated.
+	require.Len(t, proxy.hosts, 2)
+	assert.Equal(t, "abcdefghijklmnop.onion:18089", proxy.hosts[0])
+	assert.NotEmpty(t, proxy.users[0])
+	assert.NotEqual(t, proxy.users[0], proxy.users[1])
+}
+
+func TestHiddenServiceWithoutProxy(t *testing.T) {
+	t.Parallel()
+
+	client, err := mhttp.NewClient(mhttp.ClientConfig{})
+	require.NoError(t, err)
+
+	for _, host := range []string{"abc.onion", "ABC.ONION.", "abc.b32.i2p"} {
+		_, err = client.Get("http://" + host + ":18081/get_height")
+		assert.True(t, errors.Is(err, mhttp.ErrHiddenServiceWithoutProxy),
+			host)
+	}
+
+	dialer, err := mhttp.NewDialer(mhttp.ClientConfig{})
+	require.NoError(t, err)
+
+	_, err = dialer.DialContext(context.Background(), "tcp", "abc.onion:18083")
+	assert.True(t, errors.Is(err, mhttp.ErrHiddenServiceWithoutProxy))
+}
+
+func TestProxyConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, cfg := range []mhttp.ClientConfig{
+		{Proxy: "http://127.0.0.1:9050"},
+		{Proxy: "127.0.0.1"},
+		{ProxyIsolation: true},
+		{Proxy: "socks5://u:p@127.0.0.1:9050", ProxyIsolation: true},
+	} {
+		assert.Error(t, cfg.Validate(), cfg.Proxy)
+	}
+
+	assert.NoError(t, mhttp.ClientConfig{Proxy: "127.0.0.1:9050"}.Validate())
+}
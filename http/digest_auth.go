@@ -11,20 +11,49 @@ import (
 	"bytes"
 	"crypto/md5" // nolint:gosec
 	"crypto/rand"
+	"crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
+
+	"github.com/duggavo/go-monero/trace"
 )
 
+// maxDigestAuthAttempts is the number of times a request is sent before
+// giving up on getting it authorized, accounting for the nonce going stale
+// (or being deemed so when concurrent requests reach the server out of
+// order).
+//
+const maxDigestAuthAttempts = 5
+
 // DigestAuthTransport is an implementation of http.RoundTripper that takes
 // care of http digest authentication.
 //
+// The challenge from a server is remembered, so that subsequent requests to
+// it are authorized pre-emptively (incrementing the nonce count) rather than
+// having to be sent twice, with the challenge being renewed whenever the
+// server rejects its nonce (e.g., replying with `stale=true`). Should the
+// server turn out to require the nonce counts of concurrent requests to
+// arrive in order, requests to it are serialized.
+//
 type DigestAuthTransport struct {
 	Username string
 	Password string
 	rt       http.RoundTripper
+
+	mu       sync.Mutex
+	sessions map[string]*session
+
+	// serialized holds, for the hosts known to require requests to reach
+	// them in the order of their nonce counts, the lock held from
+	// authorizing a request until getting its response (see
+	// `setChallenge`).
+	//
+	serialized map[string]*sync.Mutex
 }
 
 // NewDigestAuthTransport creates a new digest transport using the
@@ -40,13 +69,22 @@ func NewDigestAuthTransport(
 	}
 }
 
+// session is the state of the authentication with a server: the challenge
+// it last sent, and how many times its nonce has been used.
+//
+type session struct {
+	challenge  *challenge
+	nonceCount int
+	cnonce     string
+}
+
 func (t *DigestAuthTransport) newCredentials(
 	req *http.Request, c *challenge,
 ) *credentials {
 	return &credentials{
 		Algorithm:  c.Algorithm,
 		DigestURI:  req.URL.RequestURI(),
-		MessageQop: c.Qop, // "auth" must be a single value
+		MessageQop: c.qop(),
 		Nonce:      c.Nonce,
 		NonceCount: 0,
 		Opaque:     c.Opaque,
@@ -58,59 +96,223 @@ func (t *DigestAuthTransport) newCredentials(
 	}
 }
 
-// RoundTrip makes a request expecting a 401 response that will require digest
-// authentication. It creates the credentials it needs and makes a follow-up
-// request.
+// RoundTrip makes a request authorized with the challenge previously received
+// from the server, if any, or otherwise expecting a 401 response that will
+// require digest authentication, in which case it creates the credentials it
+// needs and makes a follow-up request.
 //
 func (t *DigestAuthTransport) RoundTrip(
 	req *http.Request,
 ) (*http.Response, error) {
-	finalRequest := req.Clone(req.Context())
+	var bodyContents []byte
 
 	if req.Body != nil {
-		bodyContents, err := ioutil.ReadAll(req.Body)
+		var err error
+
+		bodyContents, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+
 		if err != nil {
 			return nil, fmt.Errorf("read all body: %w", err)
 		}
+	}
 
-		reqBody01 := io.NopCloser(bytes.NewBuffer(bodyContents))
-		reqBody02 := io.NopCloser(bytes.NewBuffer(bodyContents))
+	newRequest := func() *http.Request {
+		r := req.Clone(req.Context())
+		if req.Body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(bodyContents))
+		}
 
-		req.Body = reqBody01
-		finalRequest.Body = reqBody02
+		return r
 	}
 
-	// make a request to get the 401 that contains the challenge.
+	host := req.URL.Host
+
+	// send authorizes a new request with the current session, if any,
+	// and performs it.
 	//
-	resp, err := t.rt.RoundTrip(req)
+	send := func(attempt int) (*http.Response, bool, error) {
+		unlock := t.lockHost(host)
+		defer unlock()
+
+		request := newRequest()
+
+		authorized, err := t.authorize(request, host)
+		if err != nil {
+			return nil, false, fmt.Errorf("authorize: %w", err)
+		}
+
+		resp, err := t.roundTrip(request, attempt)
+		if err != nil {
+			return nil, false, fmt.Errorf("round trip err: %w", err)
+		}
+
+		return resp, authorized, nil
+	}
+
+	// without a challenge from the server yet, the first request isn't
+	// authorized, getting the 401 that contains it.
+	//
+	attempt := 1
+
+	resp, authorized, err := send(attempt)
 	if err != nil {
-		return nil, fmt.Errorf("round trip err: %w", err)
+		return nil, err
 	}
-	if resp.StatusCode != 401 { // cool, reached what we needed
-		return resp, nil
+
+	for resp.StatusCode == http.StatusUnauthorized {
+		chal, err := parseChallenges(resp.Header.Values("WWW-Authenticate"))
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("parse challenge: %w", err)
+		}
+
+		// a rejection of credentials computed from a fresh challenge
+		// is final, unless the server deems the nonce stale, and so is
+		// the last one allowed - either way, the 401 is handed back.
+		//
+		if (authorized && attempt > 1 && !chal.stale()) ||
+			attempt >= maxDigestAuthAttempts {
+			return resp, nil
+		}
+
+		// we must ensure that the response has been totally drained
+		// otherwise the http client won't reuse the connection.
+		//
+		if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
+			return nil, fmt.Errorf("copy body to null dev: %w", err)
+		}
+		resp.Body.Close()
+
+		if err := t.setChallenge(host, chal); err != nil {
+			return nil, fmt.Errorf("set challenge: %w", err)
+		}
+
+		attempt++
+
+		resp, authorized, err = send(attempt)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// we must ensure that the initial response has been totally drained
-	// otherwise the http client won't reuse the connection.
-	//
-	if _, err := io.Copy(ioutil.Discard, resp.Body); err != nil {
-		return nil, fmt.Errorf("copy body to null dev: %w", err)
+	return resp, nil
+}
+
+// authorize sets the Authorization header of `req` based on the challenge
+// last received from `host`, if any, reporting whether it did.
+//
+func (t *DigestAuthTransport) authorize(
+	req *http.Request, host string,
+) (bool, error) {
+	t.mu.Lock()
+	sess, ok := t.sessions[host]
+	if ok {
+		sess.nonceCount++
 	}
-	resp.Body.Close()
 
-	chal, err := parseChallenge(resp.Header.Get("WWW-Authenticate"))
-	if err != nil {
-		return nil, fmt.Errorf("parse challenge: %w", err)
+	var cr *credentials
+	if ok {
+		cr = t.newCredentials(req, sess.challenge)
+		cr.NonceCount = sess.nonceCount
+		cr.Cnonce = sess.cnonce
+	}
+	t.mu.Unlock()
+
+	if !ok {
+		return false, nil
 	}
 
-	cr := t.newCredentials(finalRequest, chal)
 	auth, err := cr.authorize()
 	if err != nil {
-		return nil, fmt.Errorf("authorize: %w", err)
+		return false, fmt.Errorf("authorize: %w", err)
 	}
 
-	finalRequest.Header.Set("Authorization", auth)
-	return t.rt.RoundTrip(finalRequest)
+	req.Header.Set("Authorization", auth)
+
+	return true, nil
+}
+
+// setChallenge starts a new session with `host` based on `chal`, unless its
+// nonce is that of the current session.
+//
+// With concurrent requests, a server enforcing increasing nonce counts may
+// see them out of order, deeming the nonce of the late ones stale even
+// though it's still valid: the session is then kept (rather than restarting
+// the count), and requests to the host serialized from then on so that
+// their retries go through with the next nonce count.
+//
+func (t *DigestAuthTransport) setChallenge(host string, chal *challenge) error {
+	cnonce, err := newCnonce()
+	if err != nil {
+		return fmt.Errorf("new cnonce: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.sessions == nil {
+		t.sessions = map[string]*session{}
+	}
+
+	if sess, ok := t.sessions[host]; ok &&
+		sess.challenge.Nonce == chal.Nonce &&
+		sess.challenge.Algorithm == chal.Algorithm {
+		if chal.stale() && t.serialized[host] == nil {
+			if t.serialized == nil {
+				t.serialized = map[string]*sync.Mutex{}
+			}
+
+			t.serialized[host] = &sync.Mutex{}
+		}
+
+		return nil
+	}
+
+	t.sessions[host] = &session{challenge: chal, cnonce: cnonce}
+
+	return nil
+}
+
+// lockHost serializes the requests to `host` if it requires so (see
+// `setChallenge`), giving the function releasing the lock.
+//
+func (t *DigestAuthTransport) lockHost(host string) func() {
+	t.mu.Lock()
+	mu, ok := t.serialized[host]
+	t.mu.Unlock()
+
+	if !ok {
+		return func() {}
+	}
+
+	mu.Lock()
+
+	return mu.Unlock
+}
+
+// roundTrip performs the `attempt`-th request of the authentication
+// exchange, within a span if the context of the request carries a tracer
+// (see the `trace` package).
+//
+func (t *DigestAuthTransport) roundTrip(
+	req *http.Request, attempt int,
+) (*http.Response, error) {
+	_, span := trace.Start(req.Context(), "digest auth "+req.Method,
+		trace.String(trace.AttrServerAddress, req.URL.Host),
+		trace.Int(trace.AttrDigestAuthAttempt, attempt),
+	)
+	defer span.End()
+
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetAttributes(trace.Int(trace.AttrHTTPStatusCode, resp.StatusCode))
+
+	return resp, nil
 }
 
 type challenge struct {
@@ -123,25 +325,82 @@ type challenge struct {
 	Qop       string
 }
 
-func parseChallenge(input string) (*challenge, error) {
-	const quotation = `"`
+// stale tells whether the server rejected the request due to its nonce
+// having expired rather than the credentials being wrong.
+//
+func (c *challenge) stale() bool {
+	return strings.EqualFold(c.Stale, "true")
+}
+
+// qop picks the quality of protection to use out of those offered ("auth"
+// being the only one supported, empty for none).
+//
+func (c *challenge) qop() string {
+	if c.Qop == "" {
+		return ""
+	}
+
+	for _, qop := range strings.Split(c.Qop, ",") {
+		if strings.TrimSpace(qop) == "auth" {
+			return "auth"
+		}
+	}
+
+	return c.Qop
+}
+
+// algorithms are the digest algorithms supported, from the most preferred
+// to the least.
+//
+var algorithms = []string{"SHA-256", "SHA-256-sess", "MD5", "MD5-sess"}
 
+// parseChallenges parses the challenges in the `WWW-Authenticate` headers
+// of a response, picking the one with the most preferred algorithm.
+//
+func parseChallenges(inputs []string) (*challenge, error) {
+	var (
+		best     *challenge
+		bestRank = len(algorithms)
+		firstErr error
+	)
+
+	for _, input := range inputs {
+		c, err := parseChallenge(input)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		for rank, algorithm := range algorithms {
+			if strings.EqualFold(c.Algorithm, algorithm) && rank < bestRank {
+				best, bestRank = c, rank
+			}
+		}
+	}
+
+	if best != nil {
+		return best, nil
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return nil, fmt.Errorf("no challenge with a supported algorithm "+
+		"in %q", inputs)
+}
+
+func parseChallenge(input string) (*challenge, error) {
 	fields, err := parseChallengeFields(input)
 	if err != nil {
 		return nil, fmt.Errorf("parse challenge fields: %w", err)
 	}
 
-	c := &challenge{}
-	for _, field := range fields {
-		kv := strings.SplitN(field, "=", 2)
-		if len(kv) != 2 {
-			return nil, fmt.Errorf("split: expected to 2 parts "+
-				"in entry, got %d. field: '%s'",
-				len(kv), field)
-		}
-
-		key, value := kv[0], kv[1]
-		value = strings.Trim(value, quotation)
+	c := &challenge{Algorithm: "MD5"}
+	for key, value := range fields {
 		switch key {
 		case "qop":
 			c.Qop = value
@@ -153,32 +412,81 @@ func parseChallenge(input string) (*challenge, error) {
 			c.Nonce = value
 		case "stale":
 			c.Stale = value
-		default:
-			return nil, fmt.Errorf("unknown field '%s'", key)
+		case "opaque":
+			c.Opaque = value
+		case "domain":
+			c.Domain = value
 		}
 	}
 
+	if c.Nonce == "" {
+		return nil, fmt.Errorf("no nonce in challenge")
+	}
+
 	return c, nil
 }
 
-func parseChallengeFields(str string) ([]string, error) {
+// parseChallengeFields parses the `key=value` (or `key="value"`) pairs of a
+// digest challenge.
+//
+func parseChallengeFields(str string) (map[string]string, error) {
 	const challengePrefix = "Digest "
 	const whitespaceDelimiters = " \n\r\t"
 
 	str = strings.Trim(str, whitespaceDelimiters)
-	if !strings.HasPrefix(str, challengePrefix) {
+	if len(str) < len(challengePrefix) ||
+		!strings.EqualFold(str[:len(challengePrefix)], challengePrefix) {
 		return nil, fmt.Errorf("bad challenge: "+
 			"input doesn't start with '%s'", challengePrefix)
 	}
 
-	str = strings.Trim(str[len(challengePrefix):], whitespaceDelimiters)
-	fields := strings.Split(str, ",")
-	if len(fields) != 5 {
-		return nil, fmt.Errorf("split: expected 5 fields, got %d",
-			len(fields))
-	}
+	str = str[len(challengePrefix):]
+	fields := map[string]string{}
+
+	for {
+		str = strings.TrimLeft(str, whitespaceDelimiters+",")
+		if str == "" {
+			return fields, nil
+		}
 
-	return fields, nil
+		eq := strings.IndexByte(str, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("field without value: '%s'", str)
+		}
+
+		key := strings.ToLower(strings.TrimSpace(str[:eq]))
+		str = strings.TrimLeft(str[eq+1:], whitespaceDelimiters)
+
+		var value string
+
+		if strings.HasPrefix(str, `"`) {
+			var b strings.Builder
+
+			i := 1
+			for ; i < len(str) && str[i] != '"'; i++ {
+				if str[i] == '\\' && i+1 < len(str) {
+					i++
+				}
+
+				b.WriteByte(str[i])
+			}
+
+			if i >= len(str) {
+				return nil, fmt.Errorf("unterminated value for '%s'", key)
+			}
+
+			value, str = b.String(), str[i+1:]
+		} else {
+			end := strings.IndexByte(str, ',')
+			if end < 0 {
+				end = len(str)
+			}
+
+			value, str = strings.TrimSpace(str[:end]), str[end:]
+		}
+
+		fields[key] = value
+	}
 }
 
 type credentials struct {
@@ -196,43 +504,72 @@ type credentials struct {
 	password string
 }
 
-func (c *credentials) ha1() string {
-	return h(fmt.Sprintf("%s:%s:%s", c.Username, c.Realm, c.password))
+// hash gives the hash function of the algorithm.
+//
+func (c *credentials) hash() (func() hash.Hash, error) {
+	switch strings.ToUpper(strings.TrimSuffix(
+		strings.ToLower(c.Algorithm), "-sess",
+	)) {
+	case "MD5":
+		return md5.New, nil
+	case "SHA-256":
+		return sha256.New, nil
+	}
+
+	return nil, fmt.Errorf("unsupported algorithm '%s'", c.Algorithm)
+}
+
+// sess tells whether the algorithm is a `-sess` variant, in which the
+// secret is derived from the nonces.
+//
+func (c *credentials) sess() bool {
+	return strings.HasSuffix(strings.ToLower(c.Algorithm), "-sess")
 }
 
-func (c *credentials) ha2() string {
-	return h(fmt.Sprintf("%s:%s", c.method, c.DigestURI))
+func (c *credentials) ha1(hf func() hash.Hash) string {
+	ha1 := h(hf, fmt.Sprintf("%s:%s:%s", c.Username, c.Realm, c.password))
+	if c.sess() {
+		ha1 = h(hf, fmt.Sprintf("%s:%s:%s", ha1, c.Nonce, c.Cnonce))
+	}
+
+	return ha1
+}
+
+func (c *credentials) ha2(hf func() hash.Hash) string {
+	return h(hf, fmt.Sprintf("%s:%s", c.method, c.DigestURI))
 }
 
 func (c *credentials) resp() (string, error) {
-	c.NonceCount++
+	hf, err := c.hash()
+	if err != nil {
+		return "", err
+	}
 
-	if c.MessageQop != "auth" {
-		return "", fmt.Errorf("unexpected messageqop '%s'",
-			c.MessageQop)
+	if c.NonceCount == 0 {
+		c.NonceCount++
 	}
 
-	b := make([]byte, 8)
-	_, err := io.ReadFull(rand.Reader, b)
-	if err != nil {
-		return "", fmt.Errorf("read full: %w", err)
+	if c.Cnonce == "" {
+		c.Cnonce, err = newCnonce()
+		if err != nil {
+			return "", fmt.Errorf("new cnonce: %w", err)
+		}
 	}
 
-	c.Cnonce = fmt.Sprintf("%x", b)[:16]
+	switch c.MessageQop {
+	case "":
+		return kd(hf, c.ha1(hf), fmt.Sprintf("%s:%s",
+			c.Nonce, c.ha2(hf))), nil
+	case "auth":
+		data := fmt.Sprintf("%s:%08x:%s:%s:%s",
+			c.Nonce, c.NonceCount, c.Cnonce, c.MessageQop, c.ha2(hf))
+		return kd(hf, c.ha1(hf), data), nil
+	}
 
-	data := fmt.Sprintf("%s:%08x:%s:%s:%s",
-		c.Nonce, c.NonceCount, c.Cnonce, c.MessageQop, c.ha2())
-	return kd(c.ha1(), data), nil
+	return "", fmt.Errorf("unexpected messageqop '%s'", c.MessageQop)
 }
 
 func (c *credentials) authorize() (string, error) {
-	// Note that this is only implemented for MD5 and NOT MD5-sess.
-	// MD5-sess is rarely supported and those that do are a big mess.
-	if c.Algorithm != "MD5" {
-		return "", fmt.Errorf("unsupported algorithm '%s'",
-			c.Algorithm)
-	}
-
 	resp, err := c.resp()
 	if err != nil {
 		return "", fmt.Errorf("resp: %w", err)
@@ -261,18 +598,29 @@ func (c *credentials) authorize() (string, error) {
 	return fmt.Sprintf("Digest %s", strings.Join(sl, ", ")), nil
 }
 
-func h(data string) string {
-	// `gosec` won't be happy ("weak crypto primitive"), but it's what the
-	// server uses.
+// newCnonce generates a client nonce.
+//
+func newCnonce() (string, error) {
+	b := make([]byte, 8)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", fmt.Errorf("read full: %w", err)
+	}
+
+	return fmt.Sprintf("%x", b), nil
+}
+
+func h(hf func() hash.Hash, data string) string {
+	// `gosec` won't be happy ("weak crypto primitive") about MD5, but it's
+	// what the server uses.
 	//
 	// nolint:gosec
-	hf := md5.New()
-	if _, err := io.WriteString(hf, data); err != nil {
+	hasher := hf()
+	if _, err := io.WriteString(hasher, data); err != nil {
 		panic(fmt.Errorf("write string: %w", err))
 	}
-	return fmt.Sprintf("%x", hf.Sum(nil))
+	return fmt.Sprintf("%x", hasher.Sum(nil))
 }
 
-func kd(secret, data string) string {
-	return h(fmt.Sprintf("%s:%s", secret, data))
+func kd(hf func() hash.Hash, secret, data string) string {
+	return h(hf, fmt.Sprintf("%s:%s", secret, data))
 }
@@ -1,9 +1,21 @@
 package http_test
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	mhttp "github.com/duggavo/go-monero/http"
 )
@@ -22,3 +34,295 @@ func TestParseChallenge(t *testing.T) {
 	assert.Equal(t, `IdDHjxbfpLYP/KzjaxaOqA==`, challenge.Nonce)
 	assert.Equal(t, "false", challenge.Stale)
 }
+
+// digestServer is a digest-protected handler, renewing its nonce (and
+// replying with `stale=true`) after `maxUses` requests.
+type digestServer struct {
+	algorithms []string
+	password   string
+	maxUses    int
+
+	mu        sync.Mutex
+	nonce     int
+	uses      int
+	lastNC    uint64
+	hits      int
+	algorithm string
+}
+
+func (s *digestServer) challenge(w http.ResponseWriter, stale bool) {
+	for _, algorithm := range s.algorithms {
+		w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Digest `+
+			`qop="auth",algorithm=%s,realm="monero-rpc",`+
+			`nonce="nonce-%d",stale=%t`, algorithm, s.nonce, stale))
+	}
+
+	w.WriteHeader(http.StatusUnauthorized)
+}
+
+func (s *digestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hits++
+
+	auth := r.Header.Get("Authorization")
+	if auth == "" {
+		s.challenge(w, false)
+		return
+	}
+
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, "Digest "), ", ") {
+		kv := strings.SplitN(field, "=", 2)
+		fields[kv[0]] = strings.Trim(kv[1], `"`)
+	}
+
+	nc, err := strconv.ParseUint(fields["nc"], 16, 64)
+	if err != nil || fields["nonce"] != fmt.Sprintf("nonce-%d", s.nonce) ||
+		nc <= s.lastNC {
+		s.challenge(w, true)
+		return
+	}
+
+	hf := md5.New
+	if strings.HasPrefix(fields["algorithm"], "SHA-256") {
+		hf = sha256.New
+	}
+
+	h := func(data string) string {
+		hasher := hf()
+		_, _ = hasher.Write([]byte(data))
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	ha1 := h(fields["username"] + ":monero-rpc:" + s.password)
+	if strings.HasSuffix(fields["algorithm"], "-sess") {
+		ha1 = h(ha1 + ":" + fields["nonce"] + ":" + fields["cnonce"])
+	}
+
+	expected := h(strings.Join([]string{
+		ha1, fields["nonce"], fields["nc"], fields["cnonce"], "auth",
+		h(r.Method + ":" + fields["uri"]),
+	}, ":"))
+	if fields["response"] != expected {
+		s.challenge(w, false)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+
+	s.lastNC = nc
+	s.algorithm = fields["algorithm"]
+
+	s.uses++
+	if s.uses >= s.maxUses {
+		s.nonce++
+		s.uses = 0
+		s.lastNC = 0
+	}
+
+	_, _ = w.Write(body)
+}
+
+func (s *digestServer) Hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hits
+}
+
+func TestDigestAuthTransport(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name       string
+		algorithms []string
+		password   string
+		maxUses    int
+		expected   string
+		hits       int
+		status     int
+	}{
+		{
+			name:       "md5",
+			algorithms: []string{"MD5"},
+			maxUses:    100,
+			expected:   "MD5",
+			hits:       6,
+		},
+		{
+			name:       "md5-sess",
+			algorithms: []string{"MD5-sess"},
+			maxUses:    100,
+			expected:   "MD5-sess",
+			hits:       6,
+		},
+		{
+			name:       "sha-256 preferred",
+			algorithms: []string{"MD5", "SHA-256"},
+			maxUses:    100,
+			expected:   "SHA-256",
+			hits:       6,
+		},
+		{
+			name:       "sha-256-sess",
+			algorithms: []string{"SHA-256-sess"},
+			maxUses:    100,
+			expected:   "SHA-256-sess",
+			hits:       6,
+		},
+		{
+			// the nonce goes stale after every 2 requests: the
+			// 3rd and 5th get renewed.
+			name:       "stale",
+			algorithms: []string{"MD5"},
+			maxUses:    2,
+			expected:   "MD5",
+			hits:       8,
+		},
+		{
+			name:       "wrong password",
+			algorithms: []string{"MD5"},
+			password:   "wrong",
+			maxUses:    100,
+			hits:       10,
+			status:     http.StatusUnauthorized,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			handler := &digestServer{
+				algorithms: tc.algorithms,
+				password:   "pass",
+				maxUses:    tc.maxUses,
+			}
+
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+
+			password := tc.password
+			if password == "" {
+				password = "pass"
+			}
+
+			client := &http.Client{
+				Transport: mhttp.NewDigestAuthTransport(
+					"user", password, http.DefaultTransport,
+				),
+			}
+
+			status := tc.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			for i := 0; i < 5; i++ {
+				body := fmt.Sprintf("request %d", i)
+
+				resp, err := client.Post(server.URL+"/json_rpc",
+					"application/json", strings.NewReader(body))
+				require.NoError(t, err)
+
+				b, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				resp.Body.Close()
+
+				require.Equal(t, status, resp.StatusCode)
+				if status == http.StatusOK {
+					assert.Equal(t, body, string(b))
+				}
+			}
+
+			assert.Equal(t, tc.hits, handler.Hits())
+			assert.Equal(t, tc.expected, handler.algorithm)
+		})
+	}
+}
+
+func TestDigestAuthTransportConcurrent(t *testing.T) {
+	t.Parallel()
+
+	handler := &digestServer{
+		algorithms: []string{"MD5"},
+		password:   "pass",
+		maxUses:    1000,
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: mhttp.NewDigestAuthTransport(
+			"user", "pass", http.DefaultTransport,
+		),
+	}
+
+	var wg sync.WaitGroup
+
+	statuses := make(chan int, 8*10)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				resp, err := client.Post(server.URL+"/json_rpc",
+					"application/json", strings.NewReader("body"))
+				if err != nil {
+					statuses <- 0
+					continue
+				}
+
+				_, _ = io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+
+				statuses <- resp.StatusCode
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(statuses)
+
+	for status := range statuses {
+		assert.Equal(t, http.StatusOK, status)
+	}
+}
+
+func TestDigestAuthTransportGivesUp(t *testing.T) {
+	t.Parallel()
+
+	var hits int32
+
+	// a server deeming every nonce stale.
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&hits, 1)
+
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Digest `+
+				`qop="auth",algorithm=MD5,realm="monero-rpc",`+
+				`nonce="nonce-%d",stale=true`, n))
+			w.WriteHeader(http.StatusUnauthorized)
+		},
+	))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{
+		Transport: mhttp.NewDigestAuthTransport(
+			"user", "pass", http.DefaultTransport,
+		),
+	}
+
+	resp, err := client.Get(server.URL + "/json_rpc")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&hits))
+}
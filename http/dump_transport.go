@@ -1,46 +1,434 @@
 package http
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"strings"
+	"time"
 )
 
+const (
+	// DefaultMaxDumpBodySize is the size past which dumped bodies are
+	// truncated.
+	//
+	DefaultMaxDumpBodySize = 64 << 10
+
+	// DefaultMaxDumpBlobSize is the size past which strings in dumped JSON
+	// bodies (e.g., transaction blobs) are truncated.
+	//
+	DefaultMaxDumpBlobSize = 256
+
+	// redacted replaces the values of secrets in dumps.
+	//
+	redacted = "[REDACTED]"
+)
+
+// DefaultRedactedFields are the fields of JSON bodies whose values are
+// redacted from dumps by default: passwords, seeds and private keys sent to
+// or received from the wallet, including the multisig key exchange material
+// (`multisig_info`, and `info` from `export_multisig_info`) which carries
+// shared private keys.
+//
+var DefaultRedactedFields = []string{
+	"info",
+	"key",
+	"multisig_info",
+	"new_password",
+	"old_password",
+	"password",
+	"seed",
+	"seed_offset",
+	"spendkey",
+	"tx_key",
+	"tx_key_list",
+	"viewkey",
+}
+
+// DefaultRedactedHeaders are the headers whose values are redacted from
+// dumps by default.
+//
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+}
+
+// DumpExchange describes a request and the response to it, as dumped by
+// DumpTransport.
+//
+type DumpExchange struct {
+	// Time is when the request was sent.
+	//
+	Time time.Time `json:"time"`
+
+	// Duration is how long it took to get the response.
+	//
+	Duration time.Duration `json:"-"`
+
+	// DurationMS is Duration in (fractional) milliseconds.
+	//
+	DurationMS float64 `json:"duration_ms"`
+
+	Method string `json:"method"`
+	URL    string `json:"url"`
+
+	RequestHeaders http.Header `json:"request_headers"`
+
+	// RequestBody is the (redacted and truncated) body of the request:
+	// the JSON itself for JSON bodies, or a JSON string otherwise.
+	//
+	RequestBody json.RawMessage `json:"request_body,omitempty"`
+
+	StatusCode      int         `json:"status_code,omitempty"`
+	ResponseHeaders http.Header `json:"response_headers,omitempty"`
+
+	// ResponseBody is the (redacted and truncated) body of the response,
+	// just like RequestBody.
+	//
+	ResponseBody json.RawMessage `json:"response_body,omitempty"`
+
+	// Error is the error that the round trip failed with, if any.
+	//
+	Error string `json:"error,omitempty"`
+}
+
 // DumpTransport implements the `net/http.RoundTripper` interface wrapping
-// another Roundtripper dumping to stdout both the requests and the responses
-// that it sees passing through.
+// another Roundtripper dumping both the requests and the responses that it
+// sees passing through, with secrets redacted (see `DefaultRedactedFields`
+// and `DefaultRedactedHeaders`).
 //
 type DumpTransport struct {
 	R http.RoundTripper
+
+	options dumpOptions
+}
+
+// dumpOptions is a set of options that can be overridden to tweak the
+// transport's behavior.
+//
+type dumpOptions struct {
+	Writer          io.Writer
+	Handler         func(DumpExchange)
+	JSON            bool
+	RedactedFields  map[string]bool
+	RedactedHeaders []string
+	MaxBodySize     int
+	MaxBlobSize     int
+}
+
+// DumpOption defines a functional option for overriding optional dump
+// configuration parameters.
+//
+type DumpOption func(o *dumpOptions)
+
+// WithDumpWriter is a functional option for overriding where dumps are
+// written to (`os.Stderr` by default).
+//
+func WithDumpWriter(v io.Writer) func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		o.Writer = v
+	}
+}
+
+// WithDumpJSON is a functional option for writing each exchange as a single
+// line of JSON (see `DumpExchange`) rather than in HTTP's wire format.
+//
+func WithDumpJSON() func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		o.JSON = true
+	}
+}
+
+// WithDumpHandler is a functional option for handing each exchange to `v`
+// (e.g., for passing it to a structured logger) rather than writing it.
+//
+func WithDumpHandler(v func(DumpExchange)) func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		o.Handler = v
+	}
+}
+
+// WithRedactedFields is a functional option for redacting the values of the
+// JSON fields `v` in addition to the default ones.
+//
+func WithRedactedFields(v ...string) func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		for _, field := range v {
+			o.RedactedFields[field] = true
+		}
+	}
+}
+
+// WithoutRedaction is a functional option for dumping secrets as they are.
+//
+// ps.: only meant for debugging in safe environments.
+//
+func WithoutRedaction() func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		o.RedactedFields = map[string]bool{}
+		o.RedactedHeaders = nil
+	}
+}
+
+// WithMaxDumpSizes is a functional option for overriding the sizes past
+// which bodies and strings within JSON bodies are truncated (0 for never).
+//
+func WithMaxDumpSizes(body, blob int) func(o *dumpOptions) {
+	return func(o *dumpOptions) {
+		o.MaxBodySize = body
+		o.MaxBlobSize = blob
+	}
 }
 
 // NewDumpTransport instantiates a new DumpTransport.
 //
-func NewDumpTransport(rt http.RoundTripper) *DumpTransport {
+func NewDumpTransport(rt http.RoundTripper, opts ...DumpOption) *DumpTransport {
+	options := dumpOptions{
+		Writer:          os.Stderr,
+		RedactedFields:  map[string]bool{},
+		RedactedHeaders: DefaultRedactedHeaders,
+		MaxBodySize:     DefaultMaxDumpBodySize,
+		MaxBlobSize:     DefaultMaxDumpBlobSize,
+	}
+
+	for _, field := range DefaultRedactedFields {
+		options.RedactedFields[field] = true
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return &DumpTransport{
-		R: rt,
+		R:       rt,
+		options: options,
 	}
 }
 
 // RoundTrip implements the functionality of dumping http requests and
-// responses to `stdout` for each HTTP transaction that passes through it.
+// responses for each HTTP transaction that passes through it.
 //
-// It does so by first dumping the request, then passing that down to the
-// wrapped roundtripper, and then from the response it sees, dumping it too.
+// It does so by first capturing the request, then passing that down to the
+// wrapped roundtripper, and then from the response it sees, dumping both.
 //
-
 func (d *DumpTransport) RoundTrip(h *http.Request) (*http.Response, error) {
-	requestDump, _ := httputil.DumpRequestOut(h, true)
-	fmt.Fprintln(os.Stderr, string(requestDump))
+	exchange := DumpExchange{
+		Time:   time.Now(),
+		Method: h.Method,
+		URL:    h.URL.String(),
+	}
+
+	var requestBody []byte
+
+	if h.Body != nil {
+		b, err := io.ReadAll(h.Body)
+		h.Body.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("read request body: %w", err)
+		}
+
+		requestBody = b
+		h.Body = io.NopCloser(bytes.NewReader(b))
+	}
 
 	resp, err := d.R.RoundTrip(h)
+
+	exchange.Duration = time.Since(exchange.Time)
+	exchange.DurationMS = float64(exchange.Duration) / float64(time.Millisecond)
+
+	var responseBody []byte
+
+	if err == nil {
+		responseBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+
+		if err != nil {
+			return nil, fmt.Errorf("read response body: %w", err)
+		}
+	}
+
+	d.dump(&exchange, h, requestBody, resp, responseBody, err)
+
 	if err != nil {
 		return nil, err
 	}
 
-	responseDump, _ := httputil.DumpResponse(resp, true)
-	fmt.Fprintln(os.Stderr, string(responseDump))
+	return resp, nil
+}
+
+func (d *DumpTransport) dump(
+	exchange *DumpExchange,
+	req *http.Request, requestBody []byte,
+	resp *http.Response, responseBody []byte,
+	err error,
+) {
+	reqBody := d.redactBody(requestBody, req.Header.Get("Content-Type"))
+	exchange.RequestHeaders = d.redactHeaders(req.Header)
+
+	var respBody []byte
+	if resp != nil {
+		respBody = d.redactBody(responseBody, resp.Header.Get("Content-Type"))
+		exchange.StatusCode = resp.StatusCode
+		exchange.ResponseHeaders = d.redactHeaders(resp.Header)
+	}
+
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+
+	if d.options.Handler != nil || d.options.JSON {
+		exchange.RequestBody = rawJSON(reqBody)
+		exchange.ResponseBody = rawJSON(respBody)
+
+		if d.options.Handler != nil {
+			d.options.Handler(*exchange)
+			return
+		}
+
+		b, _ := json.Marshal(exchange)
+		fmt.Fprintln(d.options.Writer, string(b))
+
+		return
+	}
+
+	clone := req.Clone(req.Context())
+	clone.Header = exchange.RequestHeaders
+	clone.Body = io.NopCloser(bytes.NewReader(reqBody))
+	clone.ContentLength = int64(len(reqBody))
+
+	requestDump, _ := httputil.DumpRequestOut(clone, true)
+	fmt.Fprintln(d.options.Writer, string(requestDump))
+
+	if resp == nil {
+		fmt.Fprintf(d.options.Writer, "error after %s: %s\n\n",
+			exchange.Duration, exchange.Error)
+		return
+	}
+
+	respClone := *resp
+	respClone.Header = exchange.ResponseHeaders
+	respClone.Body = io.NopCloser(bytes.NewReader(respBody))
+	respClone.ContentLength = int64(len(respBody))
+
+	responseDump, _ := httputil.DumpResponse(&respClone, true)
+	fmt.Fprintln(d.options.Writer, string(responseDump))
+	fmt.Fprintf(d.options.Writer, "(took %s)\n\n", exchange.Duration)
+}
+
+// redactHeaders gives a copy of `header` with secrets redacted.
+//
+func (d *DumpTransport) redactHeaders(header http.Header) http.Header {
+	clone := header.Clone()
+
+	for _, name := range d.options.RedactedHeaders {
+		if _, ok := clone[http.CanonicalHeaderKey(name)]; ok {
+			clone.Set(name, redacted)
+		}
+	}
+
+	return clone
+}
+
+// redactBody gives the representation of `body` to dump: JSON with secrets
+// redacted and long strings truncated, a summary for binary bodies, and
+// truncated to the maximum body size.
+//
+func (d *DumpTransport) redactBody(body []byte, contentType string) []byte {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if isBinary(contentType) {
+		return []byte(fmt.Sprintf("<%d bytes of binary data>", len(body)))
+	}
+
+	var v interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&v); err == nil {
+		if b, err := json.Marshal(d.redactValue(v)); err == nil {
+			body = b
+		}
+	}
+
+	max := d.options.MaxBodySize
+	if max > 0 && len(body) > max {
+		body = append(body[:max:max], fmt.Sprintf(
+			"... (%d bytes truncated)", len(body)-max)...)
+	}
+
+	return body
+}
+
+// isBinary tells whether `contentType` denotes a binary body (e.g., that of
+// requests to the `.bin` endpoints).
+//
+func isBinary(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// malformed parameters still leave the media type usable.
+		//
+		mediaType = strings.ToLower(strings.TrimSpace(
+			strings.SplitN(contentType, ";", 2)[0],
+		))
+	}
+
+	return mediaType == "application/octet-stream"
+}
+
+// redactValue walks a decoded JSON value redacting the values of the
+// redacted fields and truncating long strings.
+//
+func (d *DumpTransport) redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if d.options.RedactedFields[key] {
+				value[key] = redacted
+				continue
+			}
+
+			value[key] = d.redactValue(field)
+		}
+	case []interface{}:
+		for i := range value {
+			value[i] = d.redactValue(value[i])
+		}
+	case string:
+		max := d.options.MaxBlobSize
+		if max > 0 && len(value) > max {
+			return fmt.Sprintf("%s... (%d bytes truncated)",
+				value[:max], len(value)-max)
+		}
+	}
+
+	return v
+}
+
+// rawJSON gives `body` as is if it's valid JSON, or encoded as a JSON
+// string otherwise.
+//
+func rawJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+
+	if json.Valid(body) {
+		return body
+	}
+
+	b, _ := json.Marshal(string(body))
 
-	return resp, err
+	return b
 }
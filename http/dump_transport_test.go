@@ -0,0 +1,175 @@
+package http_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/duggavo/go-monero/http"
+)
+
+func TestDumpTransport(t *testing.T) {
+	t.Parallel()
+
+	blob := strings.Repeat("ab", 1000)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"result":{"key":"abandon ability able",` +
+				`"blob":"` + blob + `"}}`))
+		},
+	))
+	t.Cleanup(server.Close)
+
+	roundTrip := func(opts ...mhttp.DumpOption) string {
+		out := &bytes.Buffer{}
+
+		client := &http.Client{
+			Transport: mhttp.NewDumpTransport(http.DefaultTransport,
+				append([]mhttp.DumpOption{mhttp.WithDumpWriter(out)}, opts...)...,
+			),
+		}
+
+		req, err := http.NewRequest("POST", server.URL+"/json_rpc",
+			strings.NewReader(`{"method":"open_wallet",`+
+				`"params":{"filename":"w","password":"hunter2"}}`))
+		require.NoError(t, err)
+
+		req.Header.Set("Authorization", `Digest username="user"`)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// the body is still available to the caller.
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "abandon ability able")
+
+		return out.String()
+	}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		out := roundTrip()
+
+		assert.Contains(t, out, "POST /json_rpc HTTP/1.1")
+		assert.Contains(t, out, "HTTP/1.1 200 OK")
+		assert.Contains(t, out, `"filename":"w"`)
+		assert.Contains(t, out, "[REDACTED]")
+		assert.Contains(t, out, "(1744 bytes truncated)")
+
+		for _, secret := range []string{"hunter2", "abandon", `username="user"`} {
+			assert.NotContains(t, out, secret)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		out := roundTrip(mhttp.WithDumpJSON())
+		require.Equal(t, 1, strings.Count(out, "\n"))
+
+		var exchange struct {
+			Method         string      `json:"method"`
+			StatusCode     int         `json:"status_code"`
+			DurationMS     float64     `json:"duration_ms"`
+			RequestHeaders http.Header `json:"request_headers"`
+			RequestBody    struct {
+				Params map[string]string `json:"params"`
+			} `json:"request_body"`
+		}
+
+		require.NoError(t, json.Unmarshal([]byte(out), &exchange))
+		assert.Equal(t, "POST", exchange.Method)
+		assert.Equal(t, http.StatusOK, exchange.StatusCode)
+		assert.Greater(t, exchange.DurationMS, 0.0)
+		assert.Equal(t, "[REDACTED]", exchange.RequestHeaders.Get("Authorization"))
+		assert.Equal(t, "[REDACTED]", exchange.RequestBody.Params["password"])
+		assert.NotContains(t, out, "abandon")
+	})
+
+	t.Run("handler", func(t *testing.T) {
+		t.Parallel()
+
+		var exchanges []mhttp.DumpExchange
+
+		out := roundTrip(
+			mhttp.WithRedactedFields("filename"),
+			mhttp.WithDumpHandler(func(e mhttp.DumpExchange) {
+				exchanges = append(exchanges, e)
+			}),
+		)
+		assert.Empty(t, out)
+
+		require.Len(t, exchanges, 1)
+		assert.Contains(t, string(exchanges[0].RequestBody),
+			`"filename":"[REDACTED]"`)
+	})
+
+	t.Run("without redaction", func(t *testing.T) {
+		t.Parallel()
+
+		out := roundTrip(mhttp.WithoutRedaction(), mhttp.WithMaxDumpSizes(0, 0))
+
+		assert.Contains(t, out, "hunter2")
+		assert.Contains(t, out, blob)
+	})
+}
+
+func TestDumpTransportRedactsMultisigAndBinary(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+			_, _ = io.Copy(w, r.Body)
+		},
+	))
+	t.Cleanup(server.Close)
+
+	out := &bytes.Buffer{}
+	client := &http.Client{
+		Transport: mhttp.NewDumpTransport(http.DefaultTransport,
+			mhttp.WithDumpWriter(out),
+		),
+	}
+
+	for _, tc := range []struct {
+		contentType string
+		body        string
+	}{
+		{
+			contentType: "application/json",
+			body: `{"method":"make_multisig","params":{` +
+				`"multisig_info":["MultisigxV2R1secret"],"threshold":2}}`,
+		},
+		{
+			contentType: "application/json",
+			body:        `{"result":{"info":"4d6f6e65726f secret"}}`,
+		},
+		{
+			contentType: "Application/Octet-Stream; charset=binary",
+			body:        "\x01\x11\x01\x01secret",
+		},
+	} {
+		resp, err := client.Post(server.URL, tc.contentType,
+			strings.NewReader(tc.body))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.NotContains(t, out.String(), "secret")
+	assert.Contains(t, out.String(), `"threshold":2`)
+	assert.Contains(t, out.String(), "<10 bytes of binary data>")
+}
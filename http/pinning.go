@@ -0,0 +1,386 @@
+package http
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Fingerprint is the SHA-256 digest of the DER encoding of a certificate, as
+// taken by monerod's `--rpc-ssl-allowed-fingerprints`.
+//
+type Fingerprint [sha256.Size]byte
+
+// FingerprintOf computes the fingerprint of `cert`.
+//
+func FingerprintOf(cert *x509.Certificate) Fingerprint {
+	return sha256.Sum256(cert.Raw)
+}
+
+// ParseFingerprint parses a fingerprint either in plain hex
+// (`a1b2...`) or colon-separated (`A1:B2:...`) format, regardless of case.
+//
+func ParseFingerprint(s string) (Fingerprint, error) {
+	var fp Fingerprint
+
+	b, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(s), ":", ""))
+	if err != nil {
+		return fp, fmt.Errorf("decode '%s': %w", s, err)
+	}
+
+	if len(b) != len(fp) {
+		return fp, fmt.Errorf("fingerprint '%s' has %d bytes, expected %d",
+			s, len(b), len(fp))
+	}
+
+	copy(fp[:], b)
+
+	return fp, nil
+}
+
+// String gives the colon-separated representation of the fingerprint
+// (e.g., `A1:B2:...`), as printed by `openssl x509 -fingerprint -sha256`.
+//
+func (f Fingerprint) String() string {
+	parts := make([]string, len(f))
+	for i, b := range f {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// FingerprintMismatchError is the error returned when a server presents a
+// certificate whose fingerprint isn't allowed.
+//
+type FingerprintMismatchError struct {
+	// Host is the server that presented the certificate.
+	//
+	Host string
+
+	// Presented is the fingerprint of the certificate presented.
+	//
+	Presented Fingerprint
+
+	// Allowed are the fingerprints that would have been accepted.
+	//
+	Allowed []Fingerprint
+}
+
+// Error implements the error interface.
+//
+func (e *FingerprintMismatchError) Error() string {
+	allowed := make([]string, len(e.Allowed))
+	for i, fp := range e.Allowed {
+		allowed[i] = fp.String()
+	}
+
+	return fmt.Sprintf("certificate presented by '%s' has fingerprint %s, "+
+		"allowed: [%s]", e.Host, e.Presented, strings.Join(allowed, ", "))
+}
+
+// FingerprintStore persists the fingerprints trusted on first use (see
+// `WithTrustOnFirstUse`).
+//
+type FingerprintStore interface {
+	// Fingerprint gives the fingerprint trusted for `host`, if any.
+	//
+	Fingerprint(host string) (fp Fingerprint, ok bool, err error)
+
+	// SetFingerprint trusts `fp` for `host` from now on.
+	//
+	SetFingerprint(host string, fp Fingerprint) error
+
+	// TrustFingerprint trusts `fp` for `host` unless a fingerprint is
+	// already trusted for it, giving back the one trusted in either case.
+	// Checking and setting must be atomic, so that concurrent first
+	// connections presenting different certificates don't all get
+	// trusted.
+	//
+	TrustFingerprint(host string, fp Fingerprint) (Fingerprint, error)
+}
+
+// WithPinnedFingerprints makes the connection trust only certificates whose
+// fingerprint is one of `fingerprints` (in any format accepted by
+// `ParseFingerprint`), or, if certificate authorities have been set (see
+// `WithCACert`), certificates chaining up to them.
+//
+// ps.: replaces any verification set up by `WithTrustOnFirstUse`.
+//
+func WithPinnedFingerprints(fingerprints ...string) func(*tls.Config) error {
+	return func(config *tls.Config) error {
+		allowed := make([]Fingerprint, len(fingerprints))
+
+		for i, s := range fingerprints {
+			fp, err := ParseFingerprint(s)
+			if err != nil {
+				return fmt.Errorf("parse fingerprint: %w", err)
+			}
+
+			allowed[i] = fp
+		}
+
+		verify(config, func(cs tls.ConnectionState, fp Fingerprint) error {
+			for _, a := range allowed {
+				if a == fp {
+					return nil
+				}
+			}
+
+			return &FingerprintMismatchError{
+				Host:      cs.ServerName,
+				Presented: fp,
+				Allowed:   allowed,
+			}
+		})
+
+		return nil
+	}
+}
+
+// WithTrustOnFirstUse makes the connection to `host` trust the certificate
+// presented the first time it's seen, persisting its fingerprint to `store`,
+// and only that one from then on - unless certificate authorities have been
+// set (see `WithCACert`) and the certificate chains up to them.
+//
+// As the same `tls.Config` may be used for connecting to different servers,
+// clients from `NewClient` apply it to every connection with the address
+// being connected to (`TLSFingerprintStore`).
+//
+// ps.: replaces any verification set up by `WithPinnedFingerprints`.
+//
+func WithTrustOnFirstUse(store FingerprintStore, host string) func(*tls.Config) error {
+	return func(config *tls.Config) error {
+		verify(config, func(_ tls.ConnectionState, fp Fingerprint) error {
+			trusted, err := store.TrustFingerprint(host, fp)
+			if err != nil {
+				return fmt.Errorf("trust fingerprint for '%s': %w",
+					host, err)
+			}
+
+			if trusted != fp {
+				return &FingerprintMismatchError{
+					Host:      host,
+					Presented: fp,
+					Allowed:   []Fingerprint{trusted},
+				}
+			}
+
+			return nil
+		})
+
+		return nil
+	}
+}
+
+// verify replaces the verification of the chain of trust of `config` by
+// `check` on the fingerprint of the certificate presented, unless it chains
+// up to the certificate authorities of `config` (if any).
+//
+func verify(
+	config *tls.Config, check func(tls.ConnectionState, Fingerprint) error,
+) {
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("no certificate presented")
+		}
+
+		if config.RootCAs != nil {
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         config.RootCAs,
+				Intermediates: intermediates,
+			})
+			if err == nil {
+				return nil
+			}
+		}
+
+		return check(cs, FingerprintOf(cs.PeerCertificates[0]))
+	}
+}
+
+// MemoryFingerprintStore is a FingerprintStore keeping fingerprints in
+// memory only.
+//
+type MemoryFingerprintStore struct {
+	mu           sync.Mutex
+	fingerprints map[string]Fingerprint
+}
+
+// NewMemoryFingerprintStore instantiates an empty MemoryFingerprintStore.
+//
+func NewMemoryFingerprintStore() *MemoryFingerprintStore {
+	return &MemoryFingerprintStore{
+		fingerprints: map[string]Fingerprint{},
+	}
+}
+
+// Fingerprint implements FingerprintStore.
+//
+func (s *MemoryFingerprintStore) Fingerprint(host string) (Fingerprint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fp, ok := s.fingerprints[host]
+
+	return fp, ok, nil
+}
+
+// SetFingerprint implements FingerprintStore.
+//
+func (s *MemoryFingerprintStore) SetFingerprint(host string, fp Fingerprint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fingerprints[host] = fp
+
+	return nil
+}
+
+// TrustFingerprint implements FingerprintStore.
+//
+func (s *MemoryFingerprintStore) TrustFingerprint(
+	host string, fp Fingerprint,
+) (Fingerprint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if trusted, ok := s.fingerprints[host]; ok {
+		return trusted, nil
+	}
+
+	s.fingerprints[host] = fp
+
+	return fp, nil
+}
+
+// FileFingerprintStore is a FingerprintStore persisting fingerprints to a
+// file, one `<host> <fingerprint>` pair per line (similar to ssh's
+// `known_hosts`).
+//
+type FileFingerprintStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileFingerprintStore instantiates a FileFingerprintStore backed by the
+// file at `path`, created on the first fingerprint trusted.
+//
+func NewFileFingerprintStore(path string) *FileFingerprintStore {
+	return &FileFingerprintStore{path: path}
+}
+
+// Fingerprint implements FingerprintStore.
+//
+func (s *FileFingerprintStore) Fingerprint(host string) (Fingerprint, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.lookup(host)
+}
+
+// SetFingerprint implements FingerprintStore.
+//
+func (s *FileFingerprintStore) SetFingerprint(host string, fp Fingerprint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.add(host, fp)
+}
+
+// TrustFingerprint implements FingerprintStore.
+//
+// ps.: this is only atomic among the users of this FileFingerprintStore, not
+// other processes sharing the file.
+//
+func (s *FileFingerprintStore) TrustFingerprint(
+	host string, fp Fingerprint,
+) (Fingerprint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trusted, ok, err := s.lookup(host)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+
+	if ok {
+		return trusted, nil
+	}
+
+	if err := s.add(host, fp); err != nil {
+		return Fingerprint{}, err
+	}
+
+	return fp, nil
+}
+
+// lookup gives the fingerprint for `host` found in the file, if any.
+//
+func (s *FileFingerprintStore) lookup(host string) (Fingerprint, bool, error) {
+	f, err := os.Open(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Fingerprint{}, false, nil
+		}
+
+		return Fingerprint{}, false, fmt.Errorf("open '%s': %w", s.path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || fields[0] != host {
+			continue
+		}
+
+		fp, err := ParseFingerprint(fields[1])
+		if err != nil {
+			return Fingerprint{}, false, fmt.Errorf("parse '%s': %w",
+				s.path, err)
+		}
+
+		return fp, true, nil
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Fingerprint{}, false, fmt.Errorf("scan '%s': %w", s.path, err)
+	}
+
+	return Fingerprint{}, false, nil
+}
+
+// add appends the line trusting `fp` for `host` to the file.
+//
+func (s *FileFingerprintStore) add(host string, fp Fingerprint) error {
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open '%s': %w", s.path, err)
+	}
+
+	if _, err := fmt.Fprintf(f, "%s %s\n", host, fp); err != nil {
+		f.Close()
+		return fmt.Errorf("write '%s': %w", s.path, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close '%s': %w", s.path, err)
+	}
+
+	return nil
+}
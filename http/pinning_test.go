@@ -0,0 +1,185 @@
+package http_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/duggavo/go-monero/http"
+)
+
+func TestParseFingerprint(t *testing.T) {
+	t.Parallel()
+
+	const hex = "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+	fp, err := mhttp.ParseFingerprint(hex)
+	require.NoError(t, err)
+
+	colon, err := mhttp.ParseFingerprint(fp.String())
+	require.NoError(t, err)
+	assert.Equal(t, fp, colon)
+	assert.True(t, strings.HasPrefix(fp.String(), "A1:B2:C3:"))
+
+	_, err = mhttp.ParseFingerprint("a1:b2")
+	assert.Error(t, err)
+
+	_, err = mhttp.ParseFingerprint("zz")
+	assert.Error(t, err)
+}
+
+func newTLSServer(t *testing.T) (*httptest.Server, mhttp.Fingerprint) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {},
+	))
+	t.Cleanup(server.Close)
+
+	return server, mhttp.FingerprintOf(server.Certificate())
+}
+
+func TestPinnedFingerprints(t *testing.T) {
+	t.Parallel()
+
+	server, fp := newTLSServer(t)
+	other := mhttp.Fingerprint{0x01}
+
+	get := func(fingerprints ...string) error {
+		client, err := mhttp.NewClient(mhttp.ClientConfig{
+			TLSFingerprints: fingerprints,
+		})
+		require.NoError(t, err)
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	assert.NoError(t, get(other.String(), strings.ToLower(fp.String())))
+
+	err := get(other.String())
+
+	var mismatch *mhttp.FingerprintMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, fp, mismatch.Presented)
+	assert.Equal(t, []mhttp.Fingerprint{other}, mismatch.Allowed)
+	assert.Contains(t, err.Error(), fp.String())
+}
+
+func TestTrustOnFirstUse(t *testing.T) {
+	t.Parallel()
+
+	server, fp := newTLSServer(t)
+	address := server.Listener.Addr().String()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	store := mhttp.NewFileFingerprintStore(path)
+
+	get := func() error {
+		client, err := mhttp.NewClient(mhttp.ClientConfig{
+			TLSFingerprintStore: store,
+		})
+		require.NoError(t, err)
+
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	require.NoError(t, get())
+	require.NoError(t, get())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, address+" "+fp.String()+"\n", string(b))
+
+	// a different certificate for the same address is refused.
+	other := mhttp.Fingerprint{0x01}
+	require.NoError(t, os.WriteFile(path,
+		[]byte(address+" "+other.String()+"\n"), 0o600))
+
+	err = get()
+
+	var mismatch *mhttp.FingerprintMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	assert.Equal(t, address, mismatch.Host)
+	assert.Equal(t, fp, mismatch.Presented)
+}
+
+func TestTrustOnFirstUseConcurrent(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "known_hosts")
+
+	for name, store := range map[string]mhttp.FingerprintStore{
+		"memory": mhttp.NewMemoryFingerprintStore(),
+		"file":   mhttp.NewFileFingerprintStore(path),
+	} {
+		config := &tls.Config{}
+		require.NoError(t, mhttp.WithTrustOnFirstUse(store, "node:18089")(config))
+
+		// first connections racing with different certificates.
+		certs := make([]*x509.Certificate, 8)
+		errs := make([]error, len(certs))
+
+		var wg sync.WaitGroup
+
+		for i := range certs {
+			certs[i] = &x509.Certificate{Raw: []byte{byte(i)}}
+
+			wg.Add(1)
+
+			go func(i int) {
+				defer wg.Done()
+
+				errs[i] = config.VerifyConnection(tls.ConnectionState{
+					PeerCertificates: certs[i : i+1],
+				})
+			}(i)
+		}
+
+		wg.Wait()
+
+		trusted, ok, err := store.Fingerprint("node:18089")
+		require.NoError(t, err, name)
+		require.True(t, ok, name)
+
+		accepted := 0
+
+		for i, err := range errs {
+			if err == nil {
+				accepted++
+				assert.Equal(t, mhttp.FingerprintOf(certs[i]), trusted, name)
+
+				continue
+			}
+
+			var mismatch *mhttp.FingerprintMismatchError
+			require.True(t, errors.As(err, &mismatch), name)
+			assert.Equal(t, []mhttp.Fingerprint{trusted}, mismatch.Allowed, name)
+		}
+
+		assert.Equal(t, 1, accepted, name)
+	}
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(b), "\n"))
+}
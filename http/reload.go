@@ -0,0 +1,251 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// TLSMaterial is the set of TLS certificates that a TLSReloader swaps in for
+// new connections.
+//
+type TLSMaterial struct {
+	// RootCAs are the certificate authorities trusted, or nil for
+	// keeping those of the base configuration.
+	//
+	RootCAs *x509.CertPool
+
+	// ClientCertificate is the certificate presented to servers, or nil
+	// for keeping those of the base configuration.
+	//
+	ClientCertificate *tls.Certificate
+}
+
+// ParseTLSMaterial builds TLSMaterial out of PEM-encoded certificates,
+// leaving out those not supplied (empty).
+//
+func ParseTLSMaterial(caCert, clientCert, clientKey []byte) (*TLSMaterial, error) {
+	material := &TLSMaterial{}
+
+	if len(caCert) != 0 {
+		pool, err := parseCertPool(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("parse ca cert: %w", err)
+		}
+
+		material.RootCAs = pool
+	}
+
+	if len(clientCert) != 0 || len(clientKey) != 0 {
+		keypair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, fmt.Errorf("x509 key pair: %w", err)
+		}
+
+		material.ClientCertificate = &keypair
+	}
+
+	return material, nil
+}
+
+// TLSMaterialSource provides the current TLS material (e.g., fetched from a
+// secrets store).
+//
+type TLSMaterialSource func(ctx context.Context) (*TLSMaterial, error)
+
+// FileTLSMaterialSource provides TLS material read from the PEM files at the
+// paths supplied, any of them being optional (empty).
+//
+func FileTLSMaterialSource(caCert, clientCert, clientKey string) TLSMaterialSource {
+	return func(ctx context.Context) (*TLSMaterial, error) {
+		read := func(fpath string) ([]byte, error) {
+			if fpath == "" {
+				return nil, nil
+			}
+
+			b, err := os.ReadFile(fpath)
+			if err != nil {
+				return nil, fmt.Errorf("read file '%s': %w", fpath, err)
+			}
+
+			return b, nil
+		}
+
+		ca, err := read(caCert)
+		if err != nil {
+			return nil, err
+		}
+
+		cert, err := read(clientCert)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := read(clientKey)
+		if err != nil {
+			return nil, err
+		}
+
+		return ParseTLSMaterial(ca, cert, key)
+	}
+}
+
+// TLSReloader keeps the TLS material from a source up to date, applying the
+// latest one to every new connection made by clients configured with it
+// (see `ClientConfig.TLSReloader`), so that rotating certificates doesn't
+// require instantiating new clients.
+//
+type TLSReloader struct {
+	source  TLSMaterialSource
+	onError func(error)
+
+	mu       sync.RWMutex
+	material *TLSMaterial
+}
+
+// tlsReloaderOptions is a set of options that can be overridden to tweak the
+// reloader's behavior.
+//
+type tlsReloaderOptions struct {
+	OnError func(error)
+}
+
+// TLSReloaderOption defines a functional option for overriding optional
+// reloader configuration parameters.
+//
+type TLSReloaderOption func(o *tlsReloaderOptions)
+
+// WithReloadErrorHandler is a functional option for being notified of the
+// failures to reload the material from within `Run` (the material
+// previously loaded being kept).
+//
+func WithReloadErrorHandler(v func(error)) func(o *tlsReloaderOptions) {
+	return func(o *tlsReloaderOptions) {
+		o.OnError = v
+	}
+}
+
+// NewTLSReloader instantiates a TLSReloader, loading the initial material
+// from `source`.
+//
+func NewTLSReloader(
+	ctx context.Context, source TLSMaterialSource, opts ...TLSReloaderOption,
+) (*TLSReloader, error) {
+	options := &tlsReloaderOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	r := &TLSReloader{
+		source:  source,
+		onError: options.OnError,
+	}
+
+	if err := r.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("reload: %w", err)
+	}
+
+	return r, nil
+}
+
+// Reload fetches the material from the source, swapping it in for new
+// connections (keeping the previous one if the source fails or provides
+// none).
+//
+func (r *TLSReloader) Reload(ctx context.Context) error {
+	material, err := r.source(ctx)
+	if err != nil {
+		return fmt.Errorf("source: %w", err)
+	}
+
+	if material == nil {
+		return errors.New("source provided no material")
+	}
+
+	r.mu.Lock()
+	r.material = material
+	r.mu.Unlock()
+
+	return nil
+}
+
+// Run reloads the material every `interval` until `ctx` is done.
+//
+func (r *TLSReloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := r.Reload(ctx); err != nil && r.onError != nil {
+			r.onError(err)
+		}
+	}
+}
+
+// Apply sets the current material on `config`.
+//
+func (r *TLSReloader) Apply(config *tls.Config) {
+	r.mu.RLock()
+	material := r.material
+	r.mu.RUnlock()
+
+	if material.RootCAs != nil {
+		config.RootCAs = material.RootCAs
+	}
+
+	if material.ClientCertificate != nil {
+		config.Certificates = []tls.Certificate{*material.ClientCertificate}
+	}
+}
+
+// dialTLS builds the function for dialing TLS connections with a copy of
+// `config` for each, adjusted by `adjust` (given the address being dialed).
+//
+func dialTLS(
+	dialer Dialer, config *tls.Config,
+	adjust ...func(config *tls.Config, address string) error,
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, fmt.Errorf("split host port '%s': %w", address, err)
+		}
+
+		cfg := config.Clone()
+		if cfg.ServerName == "" {
+			cfg.ServerName = host
+		}
+
+		for _, fn := range adjust {
+			if err := fn(cfg, address); err != nil {
+				return nil, err
+			}
+		}
+
+		conn, err := dialer.DialContext(ctx, network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("tls handshake: %w", err)
+		}
+
+		return tlsConn, nil
+	}
+}
@@ -0,0 +1,158 @@
+package http_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/duggavo/go-monero/http"
+)
+
+// newKeyPair generates a self-signed certificate for `name`, PEM-encoded.
+func newKeyPair(t *testing.T, name string) (cert, key []byte) {
+	t.Helper()
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+	}, &priv.PublicKey, priv)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestTLSReloader(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+		},
+	))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+
+	var (
+		mu   sync.Mutex
+		name = "first"
+	)
+
+	reloader, err := mhttp.NewTLSReloader(context.Background(),
+		func(ctx context.Context) (*mhttp.TLSMaterial, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			cert, key := newKeyPair(t, name)
+			return mhttp.ParseTLSMaterial(caPEM, cert, key)
+		},
+	)
+	require.NoError(t, err)
+
+	client, err := mhttp.NewClient(mhttp.ClientConfig{
+		TLSReloader: reloader,
+	})
+	require.NoError(t, err)
+
+	get := func() string {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		b := make([]byte, 16)
+		n, _ := resp.Body.Read(b)
+
+		return string(b[:n])
+	}
+
+	assert.Equal(t, "first", get())
+
+	mu.Lock()
+	name = "second"
+	mu.Unlock()
+
+	require.NoError(t, reloader.Reload(context.Background()))
+
+	// established connections are kept as is.
+	assert.Equal(t, "first", get())
+
+	client.CloseIdleConnections()
+	assert.Equal(t, "second", get())
+}
+
+func TestTLSReloaderNilMaterial(t *testing.T) {
+	t.Parallel()
+
+	_, err := mhttp.NewTLSReloader(context.Background(),
+		func(ctx context.Context) (*mhttp.TLSMaterial, error) {
+			return nil, nil
+		},
+	)
+	assert.Error(t, err)
+
+	material := &mhttp.TLSMaterial{}
+
+	reloader, err := mhttp.NewTLSReloader(context.Background(),
+		func(ctx context.Context) (*mhttp.TLSMaterial, error) {
+			return material, nil
+		},
+	)
+	require.NoError(t, err)
+
+	material = nil
+	assert.Error(t, reloader.Reload(context.Background()))
+
+	// the previous material is kept.
+	assert.NotPanics(t, func() { reloader.Apply(&tls.Config{}) })
+}
+
+func TestParseTLSMaterial(t *testing.T) {
+	t.Parallel()
+
+	cert, key := newKeyPair(t, "client")
+
+	material, err := mhttp.ParseTLSMaterial(nil, cert, key)
+	require.NoError(t, err)
+	assert.Nil(t, material.RootCAs)
+	assert.NotNil(t, material.ClientCertificate)
+
+	_, err = mhttp.ParseTLSMaterial([]byte("garbage"), nil, nil)
+	assert.Error(t, err)
+
+	config := &tls.Config{}
+	assert.Error(t, mhttp.WithCACertPEM([]byte("garbage"))(config))
+	require.NoError(t, mhttp.WithClientCertificatePEM(cert, key)(config))
+	assert.Len(t, config.Certificates, 1)
+}
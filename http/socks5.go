@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+)
+
+// SOCKS5 protocol constants (RFC 1928 and RFC 1929).
+//
+const (
+	socks5Version = 0x05
+
+	socks5AuthNone         = 0x00
+	socks5AuthPassword     = 0x02
+	socks5AuthNoAcceptable = 0xff
+
+	socks5PasswordVersion = 0x01
+
+	socks5CommandConnect = 0x01
+
+	socks5AddrIPv4   = 0x01
+	socks5AddrDomain = 0x03
+	socks5AddrIPv6   = 0x04
+
+	socks5ReplySucceeded = 0x00
+)
+
+// socks5Replies describes the failure codes a SOCKS5 server may reply with.
+//
+var socks5Replies = map[byte]string{
+	0x01: "general server failure",
+	0x02: "connection not allowed by ruleset",
+	0x03: "network unreachable",
+	0x04: "host unreachable",
+	0x05: "connection refused",
+	0x06: "ttl expired",
+	0x07: "command not supported",
+	0x08: "address type not supported",
+}
+
+// socks5Dialer dials addresses through a SOCKS5 proxy, letting the proxy
+// resolve host names (necessary for reaching hidden services).
+//
+type socks5Dialer struct {
+	proxy    string
+	username string
+	password string
+	forward  *net.Dialer
+}
+
+// DialContext connects to `address` through the proxy.
+//
+func (d *socks5Dialer) DialContext(
+	ctx context.Context, network, address string,
+) (net.Conn, error) {
+	if network != "tcp" && network != "tcp4" && network != "tcp6" {
+		return nil, fmt.Errorf("socks5: unsupported network '%s'", network)
+	}
+
+	conn, err := d.forward.DialContext(ctx, "tcp", d.proxy)
+	if err != nil {
+		return nil, fmt.Errorf("dial proxy '%s': %w", d.proxy, err)
+	}
+
+	// the handshake is bound to the context: its deadline is applied to
+	// the connection, and canceling it interrupts any pending i/o.
+	//
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	if err := d.handshake(conn, address); err != nil {
+		conn.Close()
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("socks5: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("socks5: %w", err)
+	}
+
+	_ = conn.SetDeadline(noDeadline)
+
+	return conn, nil
+}
+
+func (d *socks5Dialer) handshake(conn net.Conn, address string) error {
+	method := byte(socks5AuthNone)
+	if d.username != "" {
+		method = socks5AuthPassword
+	}
+
+	if _, err := conn.Write([]byte{socks5Version, 1, method}); err != nil {
+		return fmt.Errorf("write greeting: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("read greeting reply: %w", err)
+	}
+
+	if reply[0] != socks5Version {
+		return fmt.Errorf("unexpected protocol version %d", reply[0])
+	}
+
+	switch reply[1] {
+	case socks5AuthNone:
+	case socks5AuthPassword:
+		if err := d.authenticate(conn); err != nil {
+			return fmt.Errorf("authenticate: %w", err)
+		}
+	case socks5AuthNoAcceptable:
+		return fmt.Errorf("no acceptable authentication method")
+	default:
+		return fmt.Errorf("unexpected authentication method %d", reply[1])
+	}
+
+	return d.connect(conn, address)
+}
+
+func (d *socks5Dialer) authenticate(conn net.Conn) error {
+	if len(d.username) > 255 || len(d.password) > 255 {
+		return fmt.Errorf("username or password too long")
+	}
+
+	req := []byte{socks5PasswordVersion, byte(len(d.username))}
+	req = append(req, d.username...)
+	req = append(req, byte(len(d.password)))
+	req = append(req, d.password...)
+
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	reply := make([]byte, 2)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("read reply: %w", err)
+	}
+
+	if reply[1] != 0x00 {
+		return fmt.Errorf("rejected with status %d", reply[1])
+	}
+
+	return nil
+}
+
+func (d *socks5Dialer) connect(conn net.Conn, address string) error {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("split host port '%s': %w", address, err)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return fmt.Errorf("parse port '%s': %w", portStr, err)
+	}
+
+	req := []byte{socks5Version, socks5CommandConnect, 0x00}
+
+	if ip := net.ParseIP(host); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			req = append(req, socks5AddrIPv4)
+			req = append(req, ip4...)
+		} else {
+			req = append(req, socks5AddrIPv6)
+			req = append(req, ip.To16()...)
+		}
+	} else {
+		if len(host) > 255 {
+			return fmt.Errorf("host name '%s' too long", host)
+		}
+
+		req = append(req, socks5AddrDomain, byte(len(host)))
+		req = append(req, host...)
+	}
+
+	req = append(req, 0, 0)
+	binary.BigEndian.PutUint16(req[len(req)-2:], uint16(port))
+
+	if _, err := conn.Write(req); err != nil {
+		return fmt.Errorf("write connect: %w", err)
+	}
+
+	reply := make([]byte, 4)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		return fmt.Errorf("read connect reply: %w", err)
+	}
+
+	if reply[1] != socks5ReplySucceeded {
+		reason, ok := socks5Replies[reply[1]]
+		if !ok {
+			reason = fmt.Sprintf("unknown error %d", reply[1])
+		}
+
+		return fmt.Errorf("connect to '%s': %s", address, reason)
+	}
+
+	// discard the address the proxy bound to.
+	//
+	var skip int
+	switch reply[3] {
+	case socks5AddrIPv4:
+		skip = net.IPv4len
+	case socks5AddrIPv6:
+		skip = net.IPv6len
+	case socks5AddrDomain:
+		size := make([]byte, 1)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return fmt.Errorf("read bound address size: %w", err)
+		}
+
+		skip = int(size[0])
+	default:
+		return fmt.Errorf("unexpected bound address type %d", reply[3])
+	}
+
+	if _, err := io.CopyN(io.Discard, conn, int64(skip+2)); err != nil {
+		return fmt.Errorf("read bound address: %w", err)
+	}
+
+	return nil
+}
@@ -14,9 +14,21 @@ func WithCACert(fpath string) func(*tls.Config) error {
 			return fmt.Errorf("read file '%s': %w", fpath, err)
 		}
 
-		pool := x509.NewCertPool()
-		if ok := pool.AppendCertsFromPEM(certBytes); !ok {
-			return fmt.Errorf("ca cert '%s' not valid", fpath)
+		if err := WithCACertPEM(certBytes)(config); err != nil {
+			return fmt.Errorf("ca cert '%s': %w", fpath, err)
+		}
+
+		return nil
+	}
+}
+
+// WithCACertPEM is like WithCACert, taking the PEM-encoded certificate(s)
+// themselves rather than a path to them.
+func WithCACertPEM(pemBytes []byte) func(*tls.Config) error {
+	return func(config *tls.Config) error {
+		pool, err := parseCertPool(pemBytes)
+		if err != nil {
+			return fmt.Errorf("parse cert pool: %w", err)
 		}
 
 		config.RootCAs = pool
@@ -43,3 +55,37 @@ func WithClientCertificate(cert, key string) func(*tls.Config) error {
 		return nil
 	}
 }
+
+// WithClientCertificatePEM is like WithClientCertificate, taking the
+// PEM-encoded certificate and key themselves rather than paths to them.
+func WithClientCertificatePEM(cert, key []byte) func(*tls.Config) error {
+	return func(config *tls.Config) error {
+		keypair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return fmt.Errorf("x509 key pair: %w", err)
+		}
+
+		config.Certificates = []tls.Certificate{keypair}
+
+		return nil
+	}
+}
+
+// WithClientKeyPair is like WithClientCertificate, taking an already loaded
+// certificate.
+func WithClientKeyPair(keypair tls.Certificate) func(*tls.Config) {
+	return func(config *tls.Config) {
+		config.Certificates = []tls.Certificate{keypair}
+	}
+}
+
+// parseCertPool builds a pool out of the PEM-encoded certificates in
+// `pemBytes`.
+func parseCertPool(pemBytes []byte) (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if ok := pool.AppendCertsFromPEM(pemBytes); !ok {
+		return nil, fmt.Errorf("no valid certificate found")
+	}
+
+	return pool, nil
+}
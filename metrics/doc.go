@@ -0,0 +1,11 @@
+// Package metrics defines the interfaces that clients from this module report
+// metrics through, along with a dependency-free implementation exposing them
+// in Prometheus' text exposition format.
+//
+//	registry := metrics.NewRegistry()
+//	http.Handle("/metrics", registry)
+//
+//	client, _ := rpc.NewClient(address, rpc.WithMetrics(registry))
+//	sub := zmq.NewClient(endpoint, topic, zmq.WithRecorder(registry))
+//
+package metrics
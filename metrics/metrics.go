@@ -0,0 +1,77 @@
+package metrics
+
+import "time"
+
+// RPCCall describes the outcome of a single request made by an `rpc.Client`.
+//
+type RPCCall struct {
+	// Kind is the kind of request (e.g., "jsonrpc", "raw", "binary").
+	//
+	Kind string
+
+	// Method is the JSONRPC method or the endpoint hit.
+	//
+	Method string
+
+	// ErrorClass is the class of the error that the call failed with
+	// (see the `ErrorClass*` constants), or empty if it succeeded.
+	//
+	ErrorClass string
+
+	// Duration is how long the call took.
+	//
+	Duration time.Duration
+
+	// BytesOut is the size of the body of the request.
+	//
+	BytesOut int64
+
+	// BytesIn is the size of the body of the response.
+	//
+	BytesIn int64
+}
+
+// Classes of errors that RPC calls may fail with.
+const (
+	// ErrorClassTransport denotes failures to reach the server or read
+	// its response.
+	ErrorClassTransport = "transport"
+
+	// ErrorClassHTTP denotes responses with a non-2xx status code.
+	ErrorClassHTTP = "http"
+
+	// ErrorClassRPC denotes JSONRPC error objects.
+	ErrorClassRPC = "rpc"
+
+	// ErrorClassStatus denotes results whose `status` isn't OK.
+	ErrorClassStatus = "status"
+
+	// ErrorClassCanceled denotes calls interrupted by their context.
+	ErrorClassCanceled = "canceled"
+)
+
+// RPCRecorder records metrics about RPC calls.
+//
+type RPCRecorder interface {
+	ObserveRPCCall(call RPCCall)
+}
+
+// ZMQRecorder records metrics about messages received via ZMQ.
+//
+type ZMQRecorder interface {
+	// ObserveZMQMessage records a message received for `topic`.
+	//
+	ObserveZMQMessage(topic string)
+
+	// ObserveZMQError records a failure to receive or process a message
+	// for `topic`.
+	//
+	ObserveZMQError(topic string)
+}
+
+// Recorder records metrics about everything in this module.
+//
+type Recorder interface {
+	RPCRecorder
+	ZMQRecorder
+}
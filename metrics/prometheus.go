@@ -0,0 +1,288 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds (in seconds) of the buckets of the
+// histogram of the latency of RPC calls.
+var DefaultBuckets = []float64{
+	.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10,
+}
+
+// contentType is the content type of Prometheus' text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Registry is a Recorder keeping metrics in memory, exposing them in
+// Prometheus' text exposition format when served over HTTP.
+//
+type Registry struct {
+	namespace string
+	buckets   []float64
+
+	mu sync.Mutex
+
+	calls         map[labels]uint64
+	errors        map[labels]uint64
+	durations     map[labels]*histogram
+	bytesSent     map[labels]uint64
+	bytesReceived map[labels]uint64
+	zmqMessages   map[labels]uint64
+	zmqErrors     map[labels]uint64
+}
+
+// histogram is a cumulative histogram of observations.
+//
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// labels is the set of label pairs identifying a series, rendered in the
+// exposition format (e.g., `method="get_info"`).
+//
+type labels string
+
+// registryOptions is a set of options that can be overridden to tweak the
+// registry's behavior.
+//
+type registryOptions struct {
+	Namespace string
+	Buckets   []float64
+}
+
+// RegistryOption defines a functional option for overriding optional
+// registry configuration parameters.
+//
+type RegistryOption func(o *registryOptions)
+
+// WithNamespace is a functional option for overriding the prefix of the
+// names of the metrics ("monero" by default).
+//
+func WithNamespace(v string) func(o *registryOptions) {
+	return func(o *registryOptions) {
+		o.Namespace = v
+	}
+}
+
+// WithBuckets is a functional option for overriding the buckets of the
+// latency histogram (`DefaultBuckets` by default).
+//
+func WithBuckets(v []float64) func(o *registryOptions) {
+	return func(o *registryOptions) {
+		o.Buckets = v
+	}
+}
+
+// NewRegistry instantiates an empty Registry.
+//
+func NewRegistry(opts ...RegistryOption) *Registry {
+	options := &registryOptions{
+		Namespace: "monero",
+		Buckets:   DefaultBuckets,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	buckets := append([]float64{}, options.Buckets...)
+	sort.Float64s(buckets)
+
+	return &Registry{
+		namespace:     options.Namespace,
+		buckets:       buckets,
+		calls:         map[labels]uint64{},
+		errors:        map[labels]uint64{},
+		durations:     map[labels]*histogram{},
+		bytesSent:     map[labels]uint64{},
+		bytesReceived: map[labels]uint64{},
+		zmqMessages:   map[labels]uint64{},
+		zmqErrors:     map[labels]uint64{},
+	}
+}
+
+// ObserveRPCCall implements RPCRecorder.
+func (r *Registry) ObserveRPCCall(call RPCCall) {
+	l := makeLabels("kind", call.Kind, "method", call.Method)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls[l]++
+
+	if call.ErrorClass != "" {
+		r.errors[makeLabels(
+			"kind", call.Kind,
+			"method", call.Method,
+			"class", call.ErrorClass,
+		)]++
+	}
+
+	h, ok := r.durations[l]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(r.buckets))}
+		r.durations[l] = h
+	}
+
+	seconds := call.Duration.Seconds()
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+
+	h.count++
+	h.sum += seconds
+
+	r.bytesSent[l] += uint64(call.BytesOut)
+	r.bytesReceived[l] += uint64(call.BytesIn)
+}
+
+// ObserveZMQMessage implements ZMQRecorder.
+func (r *Registry) ObserveZMQMessage(topic string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.zmqMessages[makeLabels("topic", topic)]++
+}
+
+// ObserveZMQError implements ZMQRecorder.
+func (r *Registry) ObserveZMQError(topic string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.zmqErrors[makeLabels("topic", topic)]++
+}
+
+// ServeHTTP implements http.Handler, serving the metrics in Prometheus' text
+// exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+
+	_ = r.WriteText(w)
+}
+
+// WriteText writes the metrics in Prometheus' text exposition format to `w`.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := &strings.Builder{}
+
+	r.writeCounter(b, "rpc_calls_total",
+		"Number of RPC calls made.", r.calls)
+	r.writeCounter(b, "rpc_errors_total",
+		"Number of RPC calls that failed, by class of error.", r.errors)
+	r.writeHistogram(b, "rpc_call_duration_seconds",
+		"Latency of RPC calls.", r.durations)
+	r.writeCounter(b, "rpc_sent_bytes_total",
+		"Bytes sent in the bodies of RPC requests.", r.bytesSent)
+	r.writeCounter(b, "rpc_received_bytes_total",
+		"Bytes received in the bodies of RPC responses.", r.bytesReceived)
+	r.writeCounter(b, "zmq_messages_total",
+		"Number of ZMQ messages received.", r.zmqMessages)
+	r.writeCounter(b, "zmq_errors_total",
+		"Number of ZMQ messages that failed to be received or processed.",
+		r.zmqErrors)
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Registry) writeHeader(b *strings.Builder, name, help, typ string) {
+	fmt.Fprintf(b, "# HELP %s_%s %s\n", r.namespace, name, help)
+	fmt.Fprintf(b, "# TYPE %s_%s %s\n", r.namespace, name, typ)
+}
+
+func (r *Registry) writeCounter(
+	b *strings.Builder, name, help string, series map[labels]uint64,
+) {
+	if len(series) == 0 {
+		return
+	}
+
+	r.writeHeader(b, name, help, "counter")
+
+	for _, l := range sortedLabels(series) {
+		fmt.Fprintf(b, "%s_%s{%s} %d\n", r.namespace, name, l, series[l])
+	}
+}
+
+func (r *Registry) writeHistogram(
+	b *strings.Builder, name, help string, series map[labels]*histogram,
+) {
+	if len(series) == 0 {
+		return
+	}
+
+	r.writeHeader(b, name, help, "histogram")
+
+	keys := make([]labels, 0, len(series))
+	for l := range series {
+		keys = append(keys, l)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	for _, l := range keys {
+		h := series[l]
+
+		for i, bound := range r.buckets {
+			fmt.Fprintf(b, "%s_%s_bucket{%s,le=\"%s\"} %d\n",
+				r.namespace, name, l, formatFloat(bound), h.counts[i])
+		}
+
+		fmt.Fprintf(b, "%s_%s_bucket{%s,le=\"+Inf\"} %d\n",
+			r.namespace, name, l, h.count)
+		fmt.Fprintf(b, "%s_%s_sum{%s} %s\n",
+			r.namespace, name, l, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_%s_count{%s} %d\n",
+			r.namespace, name, l, h.count)
+	}
+}
+
+// makeLabels renders label name/value pairs.
+func makeLabels(pairs ...string) labels {
+	parts := make([]string, 0, len(pairs)/2)
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, pairs[i]+`="`+escape(pairs[i+1])+`"`)
+	}
+
+	return labels(strings.Join(parts, ","))
+}
+
+// escape escapes a label value as required by the exposition format.
+func escape(v string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+	).Replace(v)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+func sortedLabels(series map[labels]uint64) []labels {
+	keys := make([]labels, 0, len(series))
+	for l := range series {
+		keys = append(keys, l)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	return keys
+}
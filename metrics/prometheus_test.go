@@ -0,0 +1,66 @@
+package metrics_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/metrics"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry(metrics.WithBuckets([]float64{1, 0.1}))
+
+	registry.ObserveRPCCall(metrics.RPCCall{
+		Kind:     "jsonrpc",
+		Method:   "get_info",
+		Duration: 50 * time.Millisecond,
+		BytesOut: 10,
+		BytesIn:  100,
+	})
+	registry.ObserveRPCCall(metrics.RPCCall{
+		Kind:       "jsonrpc",
+		Method:     "get_info",
+		ErrorClass: metrics.ErrorClassHTTP,
+		Duration:   500 * time.Millisecond,
+		BytesOut:   10,
+	})
+	registry.ObserveZMQMessage("json-minimal-chain_main")
+	registry.ObserveZMQError(`weird"topic`)
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, strings.HasPrefix(
+		recorder.Header().Get("Content-Type"), "text/plain; version=0.0.4",
+	))
+
+	expected := []string{
+		`# TYPE monero_rpc_calls_total counter`,
+		`monero_rpc_calls_total{kind="jsonrpc",method="get_info"} 2`,
+		`monero_rpc_errors_total{kind="jsonrpc",method="get_info",class="http"} 1`,
+		`# TYPE monero_rpc_call_duration_seconds histogram`,
+		`monero_rpc_call_duration_seconds_bucket{kind="jsonrpc",method="get_info",le="0.1"} 1`,
+		`monero_rpc_call_duration_seconds_bucket{kind="jsonrpc",method="get_info",le="1"} 2`,
+		`monero_rpc_call_duration_seconds_bucket{kind="jsonrpc",method="get_info",le="+Inf"} 2`,
+		`monero_rpc_call_duration_seconds_sum{kind="jsonrpc",method="get_info"} 0.55`,
+		`monero_rpc_call_duration_seconds_count{kind="jsonrpc",method="get_info"} 2`,
+		`monero_rpc_sent_bytes_total{kind="jsonrpc",method="get_info"} 20`,
+		`monero_rpc_received_bytes_total{kind="jsonrpc",method="get_info"} 100`,
+		`monero_zmq_messages_total{topic="json-minimal-chain_main"} 1`,
+		`monero_zmq_errors_total{topic="weird\"topic"} 1`,
+	}
+
+	lines := strings.Split(recorder.Body.String(), "\n")
+	for _, line := range expected {
+		require.Contains(t, lines, line)
+	}
+}
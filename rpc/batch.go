@@ -0,0 +1,237 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// BatchCall is a single JSONRPC method invocation that is part of a batch
+// submitted via `JSONRPCBatch`.
+type BatchCall struct {
+	// Method is the name of the JSONRPC method to invoke.
+	//
+	Method string
+
+	// Params is the set of parameters to pass to the method, if any.
+	//
+	Params interface{}
+
+	// Result is where the result of the call gets unmarshalled to.
+	//
+	Result interface{}
+
+	// Err is the error that this particular call resulted in, filled once
+	// the batch has been submitted.
+	//
+	Err error
+}
+
+// batchResponseEnvelope is the envelope of each of the responses that are
+// part of a reply to a batch request.
+type batchResponseEnvelope struct {
+	ID     string          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// JSONRPCBatch issues all of the `calls` under the JSONRPC endpoint in a
+// single JSONRPC 2.0 batch request, filling in `Result` or `Err` for each
+// of them.
+//
+// The error returned refers to the batch as a whole (e.g., failing to reach
+// the server) - the outcome of each individual call is found in its `Err`.
+//
+// Like any other call, the batch goes through the interceptors (see
+// `CallBatch`) and is retried according to the retry policy, unless any of
+// the calls must not be submitted twice.
+//
+// As not every server supports batches, if the server rejects the batch, the
+// calls are issued one at a time instead, as will be any batch submitted
+// thereafter through this client.
+func (c *Client) JSONRPCBatch(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	if atomic.LoadInt32(&c.batchUnsupported) == 1 {
+		return c.jsonrpcSequential(ctx, calls)
+	}
+
+	err := c.do(ctx, Call{
+		Kind:   CallBatch,
+		Method: batchMethod(calls),
+		Params: calls,
+	})
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, errBatchRejected) {
+		return err
+	}
+
+	atomic.StoreInt32(&c.batchUnsupported, 1)
+
+	return c.jsonrpcSequential(ctx, calls)
+}
+
+// errBatchRejected indicates that the server is not able to deal with batch
+// requests.
+var errBatchRejected = errors.New("batch rejected")
+
+// batchMethod names a batch of `calls` after the methods they invoke,
+// deduplicated.
+func batchMethod(calls []*BatchCall) string {
+	seen := map[string]bool{}
+	methods := []string{}
+
+	for _, call := range calls {
+		if !seen[call.Method] {
+			seen[call.Method] = true
+			methods = append(methods, call.Method)
+		}
+	}
+
+	return strings.Join(methods, ",")
+}
+
+// batchRejected reports whether a batch failing with `err` was rejected by
+// the server for being a batch (as opposed to, e.g., a proxy in front of it
+// failing, in which case it's worth trying again as a batch later).
+func batchRejected(err error) bool {
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	switch statusErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound,
+		http.StatusMethodNotAllowed:
+		return true
+	}
+
+	return false
+}
+
+func (c *Client) jsonrpcBatch(ctx context.Context, call *Call) error {
+	calls, ok := call.Params.([]*BatchCall)
+	if !ok {
+		return fmt.Errorf("batch with params of type %T", call.Params)
+	}
+
+	address := c.url(endpointJSONRPC)
+
+	envelopes := make([]*RequestEnvelope, len(calls))
+	callsByID := make(map[string]*BatchCall, len(calls))
+
+	for idx, call := range calls {
+		id := c.nextID()
+
+		envelopes[idx] = &RequestEnvelope{
+			ID:      id,
+			JSONRPC: versionJSONRPC,
+			Method:  call.Method,
+			Params:  call.Params,
+		}
+		callsByID[id] = call
+	}
+
+	b, err := json.Marshal(envelopes)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", address.String(), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	var raw json.RawMessage
+
+	if err := c.submitRequest(req, call, &raw); err != nil {
+		if batchRejected(err) {
+			return fmt.Errorf("%w: %v", errBatchRejected, err)
+		}
+
+		return fmt.Errorf("submit request: %w", err)
+	}
+
+	// servers not supporting batches either reply with a single error
+	// envelope (typically `-32600`, invalid request) or something that
+	// isn't an array at all.
+	//
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || raw[0] != '[' {
+		envelope := &batchResponseEnvelope{}
+		if err := json.Unmarshal(raw, envelope); err == nil &&
+			envelope.Error != nil {
+			return fmt.Errorf("%w: %v", errBatchRejected, &RPCError{
+				Code:    envelope.Error.Code,
+				Message: envelope.Error.Message,
+			})
+		}
+
+		return errBatchRejected
+	}
+
+	responses := []*batchResponseEnvelope{}
+	if err := json.Unmarshal(raw, &responses); err != nil {
+		return fmt.Errorf("unmarshal batch response: %w", err)
+	}
+
+	for _, response := range responses {
+		call, found := callsByID[response.ID]
+		if !found {
+			continue
+		}
+
+		delete(callsByID, response.ID)
+
+		if response.Error != nil && (response.Error.Code != 0 || response.Error.Message != "") {
+			call.Err = &RPCError{
+				Method:  call.Method,
+				Code:    response.Error.Code,
+				Message: response.Error.Message,
+			}
+			continue
+		}
+
+		if call.Result == nil {
+			continue
+		}
+
+		if err := json.Unmarshal(response.Result, call.Result); err != nil {
+			call.Err = fmt.Errorf("unmarshal result: %w", err)
+		}
+	}
+
+	for id, call := range callsByID {
+		call.Err = fmt.Errorf("no response for call with id '%s'", id)
+	}
+
+	return nil
+}
+
+// jsonrpcSequential submits each call in its own JSONRPC request.
+func (c *Client) jsonrpcSequential(ctx context.Context, calls []*BatchCall) error {
+	for _, call := range calls {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("ctx: %w", err)
+		}
+
+		call.Err = c.JSONRPC(ctx, call.Method, call.Params, call.Result)
+	}
+
+	return nil
+}
@@ -8,8 +8,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync/atomic"
 
+	"github.com/duggavo/go-monero/epee"
 	mhttp "github.com/duggavo/go-monero/http"
+	"github.com/duggavo/go-monero/trace"
 )
 
 const (
@@ -22,6 +26,11 @@ const (
 	// versionJSONRPC is the version of the JSONRPC format.
 	//
 	versionJSONRPC = "2.0"
+
+	// contentTypeBinary is the content type of the requests made to the
+	// endpoints speaking epee's portable storage format.
+	//
+	contentTypeBinary = "application/octet-stream"
 )
 
 // Client is a wrapper over a plain HTTP client providing methods that
@@ -40,12 +49,40 @@ type Client struct {
 	// endpoints.
 	//
 	address *url.URL
+
+	// pathPrefix is prepended to the path of every endpoint (e.g.,
+	// "/monerod" for "unix:///run/rpc.sock:/monerod").
+	//
+	pathPrefix string
+
+	// lastID is the last ID used for a JSONRPC request envelope, used for
+	// correlating responses to requests (see `nextID`).
+	//
+	lastID uint64
+
+	// batchUnsupported is set to 1 once the server has rejected a batch
+	// request, after which batches are always sent one call at a time.
+	//
+	batchUnsupported int32
+
+	// retry is the policy for retrying failed calls, if any (see
+	// `WithRetry`).
+	//
+	retry *RetryPolicy
+
+	// invoker performs calls through the chain of interceptors (see
+	// `WithInterceptors`).
+	//
+	invoker Invoker
 }
 
 // clientOptions is a set of options that can be overridden to tweak the
 // client's behavior.
 type clientOptions struct {
-	HTTPClient *http.Client
+	HTTPClient   *http.Client
+	Retry        *RetryPolicy
+	Interceptors []Interceptor
+	Tracer       trace.Tracer
 }
 
 // ClientOption defines a functional option for overriding optional client
@@ -65,6 +102,13 @@ func WithHTTPClient(v *http.Client) func(o *clientOptions) {
 //
 // The `address` might be either restricted (typically <ip>:18089) or not
 // (typically <ip>:18081).
+//
+// To reach the server through a unix domain socket, use
+// `unix:///path/to.sock`, optionally followed by a prefix to prepend to the
+// path of every endpoint (`unix:///path/to.sock:/prefix`), or
+// `unix+https://` for TLS on top of the socket. Note that a custom HTTP
+// client (see `WithHTTPClient`) must then be created with
+// `http.ClientConfig.UnixSocket` set to the same path.
 func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	options := &clientOptions{}
 
@@ -72,23 +116,38 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 		opt(options)
 	}
 
+	parsedAddress, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("url parse: %w", err)
+	}
+
+	socket, pathPrefix := "", ""
+	if isUnixAddress(parsedAddress) {
+		socket, pathPrefix, parsedAddress = parseUnixAddress(parsedAddress)
+	}
+
 	if options.HTTPClient == nil {
-		httpClient, err := mhttp.NewClient(mhttp.ClientConfig{})
+		httpClient, err := mhttp.NewClient(mhttp.ClientConfig{
+			UnixSocket: socket,
+		})
 		if err != nil {
 			return nil, fmt.Errorf("new http client: %w", err)
 		}
 		options.HTTPClient = httpClient
 	}
 
-	parsedAddress, err := url.Parse(address)
-	if err != nil {
-		return nil, fmt.Errorf("url parse: %w", err)
+	c := &Client{
+		address:    parsedAddress,
+		pathPrefix: pathPrefix,
+		http:       options.HTTPClient,
+		retry:      options.Retry,
 	}
+	c.invoker = chain(append(
+		[]Interceptor{c.traceInterceptor(options.Tracer)},
+		options.Interceptors...,
+	), c.invoke)
 
-	return &Client{
-		address: parsedAddress,
-		http:    options.HTTPClient,
-	}, nil
+	return c, nil
 }
 
 // ResponseEnvelope wraps all responses from the RPC server.
@@ -112,13 +171,21 @@ type RequestEnvelope struct {
 
 // Request makes requests to any endpoints, not assuming any particular format.
 func (c *Client) RawRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
-	address := *c.address
-	address.Path = endpoint
+	return c.do(ctx, Call{
+		Kind:   CallRaw,
+		Method: endpoint,
+		Params: params,
+		Result: response,
+	})
+}
+
+func (c *Client) rawRequest(ctx context.Context, call *Call) error {
+	address := c.url(call.Method)
 
 	var body io.Reader
 
-	if params != nil {
-		b, err := json.Marshal(params)
+	if call.Params != nil {
+		b, err := json.Marshal(call.Params)
 		if err != nil {
 			return fmt.Errorf("marshal: %w", err)
 		}
@@ -133,7 +200,58 @@ func (c *Client) RawRequest(ctx context.Context, endpoint string, params interfa
 
 	req.Header.Add("Content-Type", "application/json")
 
-	if err := c.submitRequest(req, response); err != nil {
+	if err := c.submitRequest(req, call, call.Result); err != nil {
+		return fmt.Errorf("submit request: %w", err)
+	}
+
+	return nil
+}
+
+// BinaryRequest makes a request to one of the endpoints that speak epee's
+// portable storage binary format (those ending in `.bin`), encoding `params`
+// and decoding the response into `response` with the `epee` package.
+func (c *Client) BinaryRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
+	return c.do(ctx, Call{
+		Kind:   CallBinary,
+		Method: endpoint,
+		Params: params,
+		Result: response,
+	})
+}
+
+func (c *Client) binaryRequest(ctx context.Context, call *Call) error {
+	address := c.url(call.Method)
+
+	params := call.Params
+	if params == nil {
+		params = struct{}{}
+	}
+
+	b, err := epee.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", address.String(), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", contentTypeBinary)
+
+	err = c.submit(req, call, func(body io.Reader) error {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return fmt.Errorf("read body: %w", err)
+		}
+
+		if err := epee.Unmarshal(b, call.Result); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
 		return fmt.Errorf("submit request: %w", err)
 	}
 
@@ -144,14 +262,22 @@ func (c *Client) RawRequest(ctx context.Context, endpoint string, params interfa
 // with the proper envolope for its requests and unwrapping of results for
 // responses.
 func (c *Client) JSONRPC(ctx context.Context, method string, params interface{}, response interface{}) error {
-	address := *c.address
-	address.Path = endpointJSONRPC
+	return c.do(ctx, Call{
+		Kind:   CallJSONRPC,
+		Method: method,
+		Params: params,
+		Result: response,
+	})
+}
+
+func (c *Client) jsonrpc(ctx context.Context, call *Call) error {
+	address := c.url(endpointJSONRPC)
 
 	b, err := json.Marshal(&RequestEnvelope{
-		ID:      "0",
+		ID:      c.nextID(),
 		JSONRPC: versionJSONRPC,
-		Method:  method,
-		Params:  params,
+		Method:  call.Method,
+		Params:  call.Params,
 	})
 	if err != nil {
 		return fmt.Errorf("marshal: %w", err)
@@ -165,40 +291,89 @@ func (c *Client) JSONRPC(ctx context.Context, method string, params interface{},
 	req.Header.Add("Content-Type", "application/json")
 
 	rpcResponseBody := &ResponseEnvelope{
-		Result: response,
+		Result: call.Result,
 	}
 
-	if err := c.submitRequest(req, rpcResponseBody); err != nil {
+	if err := c.submitRequest(req, call, rpcResponseBody); err != nil {
 		return fmt.Errorf("submit request: %w", err)
 	}
 
 	if rpcResponseBody.Error.Code != 0 || rpcResponseBody.Error.Message != "" {
-		return fmt.Errorf("rpc error: code=%d message=%s",
-			rpcResponseBody.Error.Code,
-			rpcResponseBody.Error.Message,
-		)
+		return &RPCError{
+			Method:  call.Method,
+			Code:    rpcResponseBody.Error.Code,
+			Message: rpcResponseBody.Error.Message,
+		}
 	}
 
 	return nil
 }
 
+// nextID generates an ID for a JSONRPC request envelope that is unique for
+// this client.
+func (c *Client) nextID() string {
+	return strconv.FormatUint(atomic.AddUint64(&c.lastID, 1), 10)
+}
+
 // submitRequest performs any generic HTTP request to the monero node targeted
-// by this client making no assumptions about a particular endpoint.
-func (c *Client) submitRequest(req *http.Request, response interface{}) error {
+// by this client making no assumptions about a particular endpoint other than
+// it replying with JSON.
+func (c *Client) submitRequest(req *http.Request, call *Call, response interface{}) error {
+	return c.submit(req, call, func(body io.Reader) error {
+		if err := json.NewDecoder(body).Decode(response); err != nil {
+			return fmt.Errorf("decode: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// submit performs the HTTP request `req`, handing the body of a successful
+// response to `decode`.
+//
+// The sizes of the request and response bodies are recorded in `call`, if
+// any.
+func (c *Client) submit(req *http.Request, call *Call, decode func(io.Reader) error) error {
 	resp, err := c.http.Do(req)
 	if err != nil {
 		return fmt.Errorf("do: %w", err)
 	}
 
-	defer resp.Body.Close()
+	body := &countingReader{Reader: resp.Body}
+
+	defer func() {
+		resp.Body.Close()
+
+		if call != nil {
+			call.BytesOut = req.ContentLength
+			call.BytesIn = body.n
+		}
+	}()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("non-2xx status code: %d", resp.StatusCode)
-	}
+		snippet, _ := io.ReadAll(io.LimitReader(body, bodySnippetSize))
 
-	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
-		return fmt.Errorf("decode: %w", err)
+		return &HTTPStatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Body:       string(snippet),
+		}
 	}
 
-	return nil
+	return decode(body)
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.Reader
+
+	n int64
+}
+
+// Read implements io.Reader.
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+
+	return n, err
 }
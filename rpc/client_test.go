@@ -0,0 +1,813 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/duggavo/go-monero/http"
+	"github.com/duggavo/go-monero/metrics"
+	"github.com/duggavo/go-monero/rpc"
+	"github.com/duggavo/go-monero/rpc/daemon"
+	"github.com/duggavo/go-monero/rpc/wallet"
+	"github.com/duggavo/go-monero/trace"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestJSONRPCErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		status   int
+		body     string
+		sentinel error
+		code     int
+	}{
+		{
+			name:     "wallet not open",
+			status:   http.StatusOK,
+			body:     `{"id":"0","jsonrpc":"2.0","error":{"code":-13,"message":"No wallet file"}}`,
+			sentinel: wallet.ErrNotOpen,
+			code:     wallet.ErrorCodeNotOpen,
+		},
+
+		{
+			name:     "daemon busy",
+			status:   http.StatusOK,
+			body:     `{"id":"0","jsonrpc":"2.0","error":{"code":-9,"message":"Core is busy"}}`,
+			sentinel: daemon.ErrBusy,
+			code:     daemon.ErrorCodeCoreBusy,
+		},
+
+		{
+			name:     "method not found",
+			status:   http.StatusOK,
+			body:     `{"id":"0","jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"}}`,
+			sentinel: rpc.ErrMethodNotFound,
+			code:     rpc.ErrorCodeMethodNotFound,
+		},
+
+		{
+			name:     "forbidden",
+			status:   http.StatusForbidden,
+			body:     `access denied`,
+			sentinel: rpc.ErrForbidden,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			})
+
+			err := client.JSONRPC(context.Background(), "foo", nil, &struct{}{})
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tc.sentinel))
+
+			if tc.code != 0 {
+				var rpcErr *rpc.RPCError
+				require.True(t, errors.As(err, &rpcErr))
+				assert.Equal(t, "foo", rpcErr.Method)
+
+				code, ok := rpc.ErrorCode(err)
+				assert.True(t, ok)
+				assert.Equal(t, tc.code, code)
+				return
+			}
+
+			var statusErr *rpc.HTTPStatusError
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tc.status, statusErr.StatusCode)
+			assert.Equal(t, tc.body, statusErr.Body)
+		})
+	}
+}
+
+func TestDaemonClientPreservesErrorChain(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"0","jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found"}}`))
+	})
+
+	_, err := daemon.NewClient(client).GetBans(context.Background())
+	require.Error(t, err)
+	assert.True(t, daemon.IsRestricted(err))
+	assert.False(t, errors.Is(err, daemon.ErrBusy))
+}
+
+func TestJSONRPCBatch(t *testing.T) {
+	t.Parallel()
+
+	type envelope struct {
+		ID     string          `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+
+	var requests int32
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		envelopes := []envelope{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&envelopes))
+
+		responses := []map[string]interface{}{}
+		for i := len(envelopes) - 1; i >= 0; i-- {
+			env := envelopes[i]
+
+			if env.Method == "fail" {
+				responses = append(responses, map[string]interface{}{
+					"id":    env.ID,
+					"error": map[string]interface{}{"code": -2, "message": "boom"},
+				})
+				continue
+			}
+
+			responses = append(responses, map[string]interface{}{
+				"id":     env.ID,
+				"result": map[string]string{"echo": string(env.Params)},
+			})
+		}
+
+		require.NoError(t, json.NewEncoder(w).Encode(responses))
+	})
+
+	type result struct {
+		Echo string `json:"echo"`
+	}
+
+	calls := []*rpc.BatchCall{
+		{Method: "echo", Params: []int{1}, Result: &result{}},
+		{Method: "fail", Result: &result{}},
+		{Method: "echo", Params: []int{3}, Result: &result{}},
+	}
+
+	require.NoError(t, client.JSONRPCBatch(context.Background(), calls))
+	assert.EqualValues(t, 1, atomic.LoadInt32(&requests))
+
+	assert.NoError(t, calls[0].Err)
+	assert.Equal(t, "[1]", calls[0].Result.(*result).Echo)
+
+	var rpcErr *rpc.RPCError
+	require.True(t, errors.As(calls[1].Err, &rpcErr))
+	assert.Equal(t, "fail", rpcErr.Method)
+	assert.Equal(t, -2, rpcErr.Code)
+
+	assert.NoError(t, calls[2].Err)
+	assert.Equal(t, "[3]", calls[2].Result.(*result).Echo)
+}
+
+func TestJSONRPCBatchFallback(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		env := map[string]interface{}{}
+		if err := json.NewDecoder(r.Body).Decode(&env); err != nil {
+			_, _ = w.Write([]byte(`{"id":0,"jsonrpc":"2.0","error":{"code":-32700,"message":"Parse error"}}`))
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":     env["id"],
+			"result": map[string]interface{}{"method": env["method"]},
+		})
+	})
+
+	type result struct {
+		Method string `json:"method"`
+	}
+
+	for _, expectedRequests := range []int32{3, 5} {
+		calls := []*rpc.BatchCall{
+			{Method: "foo", Result: &result{}},
+			{Method: "bar", Result: &result{}},
+		}
+
+		require.NoError(t, client.JSONRPCBatch(context.Background(), calls))
+		assert.Equal(t, expectedRequests, atomic.LoadInt32(&requests))
+
+		for _, call := range calls {
+			assert.NoError(t, call.Err)
+			assert.Equal(t, call.Method, call.Result.(*result).Method)
+		}
+	}
+}
+
+func TestJSONRPCBatchTransientFailure(t *testing.T) {
+	t.Parallel()
+
+	type result struct {
+		ID string `json:"id"`
+	}
+
+	// batchServer fails with each of `statuses` in turn, then replies to
+	// batches.
+	batchServer := func(statuses ...int) (http.HandlerFunc, *int32) {
+		var requests int32
+
+		return func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&requests, 1)
+			if int(n) <= len(statuses) {
+				w.WriteHeader(statuses[n-1])
+				return
+			}
+
+			envelopes := []map[string]interface{}{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&envelopes))
+
+			responses := []map[string]interface{}{}
+			for _, env := range envelopes {
+				responses = append(responses, map[string]interface{}{
+					"id":     env["id"],
+					"result": map[string]interface{}{"id": env["id"]},
+				})
+			}
+
+			require.NoError(t, json.NewEncoder(w).Encode(responses))
+		}, &requests
+	}
+
+	batch := func(methods ...string) []*rpc.BatchCall {
+		calls := []*rpc.BatchCall{}
+		for _, method := range methods {
+			calls = append(calls, &rpc.BatchCall{
+				Method: method, Result: &result{},
+			})
+		}
+
+		return calls
+	}
+
+	t.Run("batching kept", func(t *testing.T) {
+		t.Parallel()
+
+		handler, requests := batchServer(http.StatusBadGateway)
+		client := newTestClient(t, handler)
+
+		err := client.JSONRPCBatch(context.Background(), batch("a", "b"))
+		assert.True(t, errors.Is(err, &rpc.HTTPStatusError{StatusCode: 502}))
+
+		calls := batch("a", "b")
+		require.NoError(t, client.JSONRPCBatch(context.Background(), calls))
+		assert.NoError(t, calls[1].Err)
+		assert.EqualValues(t, 2, atomic.LoadInt32(requests))
+	})
+
+	t.Run("retried and intercepted", func(t *testing.T) {
+		t.Parallel()
+
+		handler, requests := batchServer(http.StatusServiceUnavailable)
+
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		var seen []rpc.Call
+
+		client, err := rpc.NewClient(server.URL,
+			rpc.WithRetry(rpc.RetryPolicy{InitialBackoff: time.Millisecond}),
+			rpc.WithInterceptors(func(
+				ctx context.Context, call *rpc.Call, next rpc.Invoker,
+			) error {
+				err := next(ctx, call)
+				seen = append(seen, *call)
+				return err
+			}),
+		)
+		require.NoError(t, err)
+
+		calls := batch("a", "b", "a")
+		require.NoError(t, client.JSONRPCBatch(context.Background(), calls))
+		assert.NoError(t, calls[2].Err)
+		assert.EqualValues(t, 2, atomic.LoadInt32(requests))
+
+		require.Len(t, seen, 2)
+		for _, call := range seen {
+			assert.Equal(t, rpc.CallBatch, call.Kind)
+			assert.Equal(t, "a,b", call.Method)
+		}
+
+		assert.NotZero(t, seen[1].BytesIn)
+	})
+
+	t.Run("non-idempotent not retried", func(t *testing.T) {
+		t.Parallel()
+
+		handler, requests := batchServer(http.StatusServiceUnavailable)
+		client := newRetryingTestClient(t,
+			rpc.RetryPolicy{InitialBackoff: time.Millisecond}, handler)
+
+		err := client.JSONRPCBatch(context.Background(),
+			batch("get_balance", "transfer"))
+		assert.Error(t, err)
+		assert.EqualValues(t, 1, atomic.LoadInt32(requests))
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		t.Parallel()
+
+		handler, requests := batchServer(http.StatusMethodNotAllowed)
+
+		server := httptest.NewServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				if atomic.LoadInt32(requests) == 0 {
+					handler(w, r)
+					return
+				}
+
+				atomic.AddInt32(requests, 1)
+				env := map[string]interface{}{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&env))
+				require.NoError(t, json.NewEncoder(w).Encode(
+					map[string]interface{}{"id": env["id"], "result": env},
+				))
+			},
+		))
+		t.Cleanup(server.Close)
+
+		client, err := rpc.NewClient(server.URL)
+		require.NoError(t, err)
+
+		for _, expected := range []int32{3, 5} {
+			calls := batch("a", "b")
+			require.NoError(t, client.JSONRPCBatch(context.Background(), calls))
+			assert.NoError(t, calls[1].Err)
+			assert.Equal(t, expected, atomic.LoadInt32(requests))
+		}
+	})
+}
+
+func newRetryingTestClient(
+	t *testing.T, policy rpc.RetryPolicy, handler http.HandlerFunc,
+) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL, rpc.WithRetry(policy))
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		method   string
+		replies  []string
+		policy   rpc.RetryPolicy
+		requests int32
+		retries  int
+		wantErr  bool
+	}{
+		{
+			name:     "server error then ok",
+			method:   "get_info",
+			replies:  []string{"503", `{"status":"OK"}`},
+			requests: 2,
+			retries:  1,
+		},
+
+		{
+			name:     "busy status then ok",
+			method:   "get_info",
+			replies:  []string{`{"status":"BUSY"}`, `{"status":"OK"}`},
+			requests: 2,
+			retries:  1,
+		},
+
+		{
+			name:     "busy error then ok",
+			method:   "get_info",
+			replies:  []string{"busy", `{"status":"OK"}`},
+			requests: 2,
+			retries:  1,
+		},
+
+		{
+			name:     "attempts exhausted",
+			method:   "get_info",
+			replies:  []string{"503", "503", "503"},
+			policy:   rpc.RetryPolicy{MaxAttempts: 2},
+			requests: 2,
+			retries:  1,
+			wantErr:  true,
+		},
+
+		{
+			name:     "invalid params",
+			method:   "get_info",
+			replies:  []string{"invalid", `{"status":"OK"}`},
+			requests: 1,
+			wantErr:  true,
+		},
+
+		{
+			name:     "connection closed then ok",
+			method:   "get_info",
+			replies:  []string{"close", `{"status":"OK"}`},
+			requests: 2,
+			retries:  1,
+		},
+
+		{
+			name:     "undecodable response",
+			method:   "get_info",
+			replies:  []string{"garbage", `{"status":"OK"}`},
+			requests: 1,
+			wantErr:  true,
+		},
+
+		{
+			name:     "non-idempotent",
+			method:   "transfer",
+			replies:  []string{"503", `{"status":"OK"}`},
+			requests: 1,
+			wantErr:  true,
+		},
+
+		{
+			name:     "non-idempotent allowed",
+			method:   "transfer",
+			replies:  []string{"503", `{"status":"OK"}`},
+			policy:   rpc.RetryPolicy{AllowNonIdempotent: []string{"transfer"}},
+			requests: 2,
+			retries:  1,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests int32
+			var retries []rpc.RetryEvent
+
+			tc.policy.InitialBackoff = time.Millisecond
+			tc.policy.OnRetry = func(ev rpc.RetryEvent) {
+				retries = append(retries, ev)
+			}
+
+			client := newRetryingTestClient(t, tc.policy,
+				func(w http.ResponseWriter, r *http.Request) {
+					reply := tc.replies[atomic.AddInt32(&requests, 1)-1]
+
+					switch reply {
+					case "503":
+						w.WriteHeader(http.StatusServiceUnavailable)
+					case "busy":
+						_, _ = w.Write([]byte(`{"error":{"code":-9,"message":"Core is busy"}}`))
+					case "invalid":
+						_, _ = w.Write([]byte(`{"error":{"code":-32602,"message":"Invalid params"}}`))
+					case "close":
+						conn, _, err := w.(http.Hijacker).Hijack()
+						require.NoError(t, err)
+						conn.Close()
+					case "garbage":
+						_, _ = w.Write([]byte(`not json`))
+					default:
+						_, _ = w.Write([]byte(`{"result":` + reply + `}`))
+					}
+				},
+			)
+
+			result := &daemon.GetInfoResult{}
+			err := client.JSONRPC(context.Background(), tc.method, nil, result)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, daemon.StatusOK, result.Status)
+			}
+
+			assert.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+			require.Len(t, retries, tc.retries)
+
+			for i, ev := range retries {
+				assert.Equal(t, tc.method, ev.Method)
+				assert.Equal(t, i+2, ev.Attempt)
+				assert.Error(t, ev.Err)
+			}
+		})
+	}
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	client := newRetryingTestClient(t,
+		rpc.RetryPolicy{InitialBackoff: time.Hour, MaxBackoff: time.Hour},
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	err := client.JSONRPC(ctx, "get_info", nil, &daemon.GetInfoResult{})
+	assert.True(t, errors.Is(err, &rpc.HTTPStatusError{StatusCode: 503}))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
+func TestInterceptors(t *testing.T) {
+	t.Parallel()
+
+	var order []string
+	var seenErr error
+
+	record := func(name string) rpc.Interceptor {
+		return func(ctx context.Context, call *rpc.Call, next rpc.Invoker) error {
+			order = append(order, name+">"+call.Method)
+			err := next(ctx, call)
+			order = append(order, name+"<"+call.Method)
+
+			return err
+		}
+	}
+
+	// rewrite the params of `get_block_header_by_height`, and serve
+	// `get_height` without hitting the server at all.
+	rewrite := func(ctx context.Context, call *rpc.Call, next rpc.Invoker) error {
+		switch call.Method {
+		case "get_block_header_by_height":
+			call.Params = map[string]uint64{"height": 42}
+		case "/get_height":
+			call.Result.(*daemon.GetHeightResult).Height = 7
+			return nil
+		}
+
+		err := next(ctx, call)
+		seenErr = err
+
+		return err
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			req := struct {
+				Params struct {
+					Height uint64 `json:"height"`
+				} `json:"params"`
+			}{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, uint64(42), req.Params.Height)
+
+			_, _ = w.Write([]byte(`{"error":{"code":-2,"message":"Too big height"}}`))
+		},
+	))
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL, rpc.WithInterceptors(
+		record("a"), record("b"), rewrite,
+	))
+	require.NoError(t, err)
+
+	d := daemon.NewClient(client)
+
+	height, err := d.GetHeight(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), height.Height)
+
+	_, err = d.GetBlockHeaderByHeight(context.Background(), 1)
+	assert.True(t, errors.Is(err, daemon.ErrTooBigHeight))
+	assert.True(t, errors.Is(seenErr, daemon.ErrTooBigHeight))
+
+	assert.Equal(t, []string{
+		"a>/get_height", "b>/get_height", "b</get_height", "a</get_height",
+		"a>get_block_header_by_height", "b>get_block_header_by_height",
+		"b<get_block_header_by_height", "a<get_block_header_by_height",
+	}, order)
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	registry := metrics.NewRegistry()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/get_height" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"result":{"status":"BUSY"}}`))
+		},
+	))
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL, rpc.WithMetrics(registry))
+	require.NoError(t, err)
+
+	d := daemon.NewClient(client)
+
+	_, err = d.GetInfo(context.Background())
+	assert.True(t, errors.Is(err, daemon.ErrBusy))
+
+	_, err = d.GetHeight(context.Background())
+	var statusErr *rpc.HTTPStatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusInternalServerError, statusErr.StatusCode)
+
+	b := &strings.Builder{}
+	require.NoError(t, registry.WriteText(b))
+
+	out := b.String()
+	assert.Contains(t, out,
+		`monero_rpc_errors_total{kind="jsonrpc",method="get_info",class="status"} 1`)
+	assert.Contains(t, out,
+		`monero_rpc_errors_total{kind="raw",method="/get_height",class="http"} 1`)
+	assert.Contains(t, out,
+		`monero_rpc_received_bytes_total{kind="jsonrpc",method="get_info"} 28`)
+}
+
+// recordedSpan is a span captured by recordingTracer.
+type recordedSpan struct {
+	name   string
+	parent string
+	attrs  map[string]interface{}
+	errs   []error
+	ended  bool
+}
+
+func (s *recordedSpan) SetAttributes(attrs ...trace.Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+func (s *recordedSpan) End()                  { s.ended = true }
+
+// recordingTracer records the spans it starts, in order.
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) Start(
+	ctx context.Context, name string, attrs ...trace.Attribute,
+) (context.Context, trace.Span) {
+	span := &recordedSpan{name: name, attrs: map[string]interface{}{}}
+	span.SetAttributes(attrs...)
+
+	if parent, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+		span.parent = parent.name
+	}
+
+	r.spans = append(r.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.Header().Set("WWW-Authenticate", `Digest qop="auth",`+
+					`algorithm=MD5,realm="monero-rpc",nonce="abc",stale=false`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			_, _ = w.Write([]byte(`{"result":{"status":"OK","untrusted":true}}`))
+		},
+	))
+	t.Cleanup(server.Close)
+
+	httpClient, err := mhttp.NewClient(mhttp.ClientConfig{
+		Username: "user",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	tracer := &recordingTracer{}
+
+	client, err := rpc.NewClient(server.URL,
+		rpc.WithHTTPClient(httpClient),
+		rpc.WithTracer(tracer),
+	)
+	require.NoError(t, err)
+
+	_, err = daemon.NewClient(client).GetInfo(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, tracer.spans, 3)
+
+	call := tracer.spans[0]
+	assert.Equal(t, "jsonrpc get_info", call.name)
+	assert.Equal(t, "get_info", call.attrs[trace.AttrRPCMethod])
+	assert.Equal(t, "/json_rpc", call.attrs[trace.AttrRPCEndpoint])
+	assert.Equal(t, server.Listener.Addr().String(),
+		call.attrs[trace.AttrServerAddress])
+	assert.Equal(t, "OK", call.attrs[trace.AttrRPCStatus])
+	assert.Equal(t, true, call.attrs[trace.AttrRPCUntrusted])
+	assert.Empty(t, call.errs)
+	assert.True(t, call.ended)
+
+	for i, code := range []int{http.StatusUnauthorized, http.StatusOK} {
+		auth := tracer.spans[i+1]
+		assert.Equal(t, "jsonrpc get_info", auth.parent)
+		assert.Equal(t, i+1, auth.attrs[trace.AttrDigestAuthAttempt])
+		assert.Equal(t, code, auth.attrs[trace.AttrHTTPStatusCode])
+		assert.True(t, auth.ended)
+	}
+}
+
+func TestUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+
+	newServer := func(t *testing.T) (*httptest.Server, string) {
+		socket := filepath.Join(t.TempDir(), "rpc.sock")
+
+		l, err := net.Listen("unix", socket)
+		require.NoError(t, err)
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				paths = append(paths, r.URL.Path)
+				_, _ = w.Write([]byte(`{"result":{"count":10}}`))
+			},
+		))
+		server.Listener = l
+		t.Cleanup(server.Close)
+
+		return server, socket
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		server, socket := newServer(t)
+		server.Start()
+
+		client, err := rpc.NewClient("unix://" + socket)
+		require.NoError(t, err)
+
+		res, err := daemon.NewClient(client).GetBlockCount(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(10), res.Count)
+		assert.Equal(t, "/json_rpc", paths[len(paths)-1])
+	})
+
+	t.Run("tls with prefix", func(t *testing.T) {
+		server, socket := newServer(t)
+		server.StartTLS()
+
+		httpClient, err := mhttp.NewClient(mhttp.ClientConfig{
+			UnixSocket:    socket,
+			TLSSkipVerify: true,
+		})
+		require.NoError(t, err)
+
+		client, err := rpc.NewClient("unix+https://"+socket+":/monerod/",
+			rpc.WithHTTPClient(httpClient))
+		require.NoError(t, err)
+
+		_, err = daemon.NewClient(client).GetBlockCount(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "/monerod/json_rpc", paths[len(paths)-1])
+	})
+}
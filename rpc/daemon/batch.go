@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/duggavo/go-monero/rpc"
+)
+
+// BatchRequester is implemented by requesters that are able to submit
+// several JSONRPC calls in a single request (e.g., `*rpc.Client`).
+type BatchRequester interface {
+	// JSONRPCBatch submits all `calls` at once, filling each one's
+	// `Result` or `Err`.
+	//
+	JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error
+}
+
+// JSONRPCBatch submits all `calls` in a single request if the underlying
+// requester implements `BatchRequester`, or one by one otherwise, validating
+// the footer of every result.
+func (c *Client) JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	if batcher, ok := c.Requester.(BatchRequester); ok {
+		if err := batcher.JSONRPCBatch(ctx, calls); err != nil {
+			return err
+		}
+	} else {
+		for _, call := range calls {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("ctx: %w", err)
+			}
+
+			call.Err = c.Requester.JSONRPC(
+				ctx, call.Method, call.Params, call.Result,
+			)
+		}
+	}
+
+	for _, call := range calls {
+		if call.Err == nil {
+			call.Err = c.checkResult(call.Method, call.Result)
+		}
+	}
+
+	return nil
+}
+
+// GetBlockHeadersByHeights retrieves the headers of the blocks at each of the
+// `heights` in as few round trips as possible.
+func (c *Client) GetBlockHeadersByHeights(
+	ctx context.Context, heights []uint64,
+) ([]*GetBlockHeaderByHeightResult, error) {
+	resps := make([]*GetBlockHeaderByHeightResult, len(heights))
+	calls := make([]*rpc.BatchCall, len(heights))
+
+	for idx, height := range heights {
+		resps[idx] = &GetBlockHeaderByHeightResult{}
+		calls[idx] = &rpc.BatchCall{
+			Method: "get_block_header_by_height",
+			Params: map[string]interface{}{
+				"height": height,
+			},
+			Result: resps[idx],
+		}
+	}
+
+	if err := c.submitBatch(ctx, calls, heights); err != nil {
+		return nil, err
+	}
+
+	return resps, nil
+}
+
+// GetBlocksByHeights fetches full block information for the blocks at each of
+// the `heights` in as few round trips as possible.
+func (c *Client) GetBlocksByHeights(
+	ctx context.Context, heights []uint64,
+) ([]*GetBlockResult, error) {
+	resps := make([]*GetBlockResult, len(heights))
+	calls := make([]*rpc.BatchCall, len(heights))
+
+	for idx, height := range heights {
+		resps[idx] = &GetBlockResult{}
+		calls[idx] = &rpc.BatchCall{
+			Method: "get_block",
+			Params: GetBlockRequestParameters{
+				Height: height,
+			},
+			Result: resps[idx],
+		}
+	}
+
+	if err := c.submitBatch(ctx, calls, heights); err != nil {
+		return nil, err
+	}
+
+	return resps, nil
+}
+
+// OnGetBlockHashes retrieves the hashes of the blocks at each of the
+// `heights` in as few round trips as possible.
+func (c *Client) OnGetBlockHashes(
+	ctx context.Context, heights []uint64,
+) ([]string, error) {
+	resps := make([]string, len(heights))
+	calls := make([]*rpc.BatchCall, len(heights))
+
+	for idx, height := range heights {
+		calls[idx] = &rpc.BatchCall{
+			Method: "on_get_block_hash",
+			Params: []uint64{height},
+			Result: &resps[idx],
+		}
+	}
+
+	if err := c.submitBatch(ctx, calls, heights); err != nil {
+		return nil, err
+	}
+
+	return resps, nil
+}
+
+// submitBatch submits a batch of calls where each one refers to a particular
+// height, failing with the first error found.
+func (c *Client) submitBatch(
+	ctx context.Context, calls []*rpc.BatchCall, heights []uint64,
+) error {
+	if err := c.JSONRPCBatch(ctx, calls); err != nil {
+		return fmt.Errorf("jsonrpc batch: %w", err)
+	}
+
+	for idx, call := range calls {
+		if call.Err != nil {
+			return fmt.Errorf("jsonrpc (height %d): %w",
+				heights[idx], call.Err)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package daemon
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // Requester is responsible for making concrete request to Monero's endpoints,
 // i.e., either `jsonrpc` methods or those "raw" endpoints.
@@ -25,18 +28,162 @@ type Requester interface {
 	) error
 }
 
+// BinaryRequester is implemented by requesters that are also able to hit
+// the endpoints speaking epee's portable storage binary format (e.g.,
+// `*rpc.Client`).
+//
+type BinaryRequester interface {
+	// BinaryRequest is used for making a request to an endpoint
+	// `endpoint` whose request and response are encoded in epee's
+	// binary format.
+	//
+	BinaryRequest(
+		ctx context.Context,
+		endpoint string,
+		params interface{},
+		response interface{},
+	) error
+}
+
 // Client provides access to the daemon's JSONRPC methods and regular
 // endpoints.
 //
 type Client struct {
 	Requester
+
+	// skipStatusCheck indicates that results should be handed back as is,
+	// regardless of the `status` the daemon filled them with.
+	//
+	skipStatusCheck bool
+
+	// rejectUntrusted indicates that results flagged as `untrusted` (i.e.,
+	// served by a node in bootstrap mode) should be turned into errors.
+	//
+	rejectUntrusted bool
+}
+
+// clientOptions is a set of options that can be overridden to tweak the
+// client's behavior.
+//
+type clientOptions struct {
+	SkipStatusCheck bool
+	RejectUntrusted bool
+}
+
+// ClientOption defines a functional option for overriding optional client
+// configuration parameters.
+//
+type ClientOption func(o *clientOptions)
+
+// WithoutStatusCheck is a functional option for disabling the validation of
+// the `status` field of the results, leaving it up to the caller to inspect
+// the raw value.
+//
+func WithoutStatusCheck() func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.SkipStatusCheck = true
+	}
+}
+
+// WithRejectUntrusted is a functional option for making the client fail with
+// `ErrUntrusted` whenever the daemon reports that the result has been
+// obtained via a bootstrap daemon, and thus shouldn't be trusted.
+//
+func WithRejectUntrusted() func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.RejectUntrusted = true
+	}
 }
 
 // NewClient instantiates a new client for interacting with monero's daemon
 // api.
 //
-func NewClient(c Requester) *Client {
+func NewClient(c Requester, opts ...ClientOption) *Client {
+	options := &clientOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return &Client{
-		Requester: c,
+		Requester:       c,
+		skipStatusCheck: options.SkipStatusCheck,
+		rejectUntrusted: options.RejectUntrusted,
 	}
 }
+
+// JSONRPC calls the JSONRPC method `method` via the underlying requester,
+// validating the footer of the result once it's been unmarshalled.
+//
+func (c *Client) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	if err := c.Requester.JSONRPC(ctx, method, params, result); err != nil {
+		return err
+	}
+
+	return c.checkResult(method, result)
+}
+
+// RawRequest makes a request to `endpoint` via the underlying requester,
+// validating the footer of the response once it's been unmarshalled.
+//
+func (c *Client) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	err := c.Requester.RawRequest(ctx, endpoint, params, response)
+	if err != nil {
+		return err
+	}
+
+	return c.checkResult(endpoint, response)
+}
+
+// BinaryRequest makes a request to the binary endpoint `endpoint` via the
+// underlying requester, validating the footer of the response once it's been
+// decoded.
+//
+// It fails with `ErrBinaryUnsupported` if the underlying requester doesn't
+// implement `BinaryRequester`.
+//
+func (c *Client) BinaryRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	requester, ok := c.Requester.(BinaryRequester)
+	if !ok {
+		return ErrBinaryUnsupported
+	}
+
+	err := requester.BinaryRequest(ctx, endpoint, params, response)
+	if err != nil {
+		return err
+	}
+
+	return c.checkResult(endpoint, response)
+}
+
+// checkResult verifies that the footer of a result, if any, denotes a
+// successful and (when required) trusted reply.
+//
+func (c *Client) checkResult(method string, result interface{}) error {
+	footered, ok := result.(interface {
+		Footer() *RPCResultFooter
+	})
+	if !ok {
+		return nil
+	}
+
+	footer := footered.Footer()
+
+	if !c.skipStatusCheck {
+		if err := footer.Err(); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+	}
+
+	if c.rejectUntrusted && footer.Untrusted {
+		return fmt.Errorf("%s: %w", method, ErrUntrusted)
+	}
+
+	return nil
+}
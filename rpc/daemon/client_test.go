@@ -0,0 +1,676 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/epee"
+	"github.com/duggavo/go-monero/rpc"
+	"github.com/duggavo/go-monero/rpc/daemon"
+)
+
+// fakeRequester replies to every request with a canned JSON body.
+type fakeRequester struct {
+	body string
+}
+
+func (r *fakeRequester) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return json.Unmarshal([]byte(r.body), result)
+}
+
+func (r *fakeRequester) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return json.Unmarshal([]byte(r.body), response)
+}
+
+// newTestClient instantiates a client for a server handling requests with
+// `handler`.
+func newTestClient(t *testing.T, handler http.HandlerFunc) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL)
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestClientStatusCheck(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		body     string
+		opts     []daemon.ClientOption
+		sentinel error
+		status   string
+	}{
+		{
+			name: "ok",
+			body: `{"status":"OK"}`,
+		},
+
+		{
+			name:     "busy",
+			body:     `{"status":"BUSY"}`,
+			sentinel: daemon.ErrBusy,
+			status:   daemon.StatusBusy,
+		},
+
+		{
+			name:     "payment required",
+			body:     `{"status":"PAYMENT REQUIRED"}`,
+			sentinel: daemon.ErrPaymentRequired,
+			status:   daemon.StatusPaymentRequired,
+		},
+
+		{
+			name:   "failed",
+			body:   `{"status":"Failed"}`,
+			status: daemon.StatusFailed,
+		},
+
+		{
+			name: "failed w/ check disabled",
+			body: `{"status":"Failed"}`,
+			opts: []daemon.ClientOption{daemon.WithoutStatusCheck()},
+		},
+
+		{
+			name:     "untrusted rejected",
+			body:     `{"status":"OK","untrusted":true}`,
+			opts:     []daemon.ClientOption{daemon.WithRejectUntrusted()},
+			sentinel: daemon.ErrUntrusted,
+		},
+
+		{
+			name: "untrusted accepted",
+			body: `{"status":"OK","untrusted":true}`,
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := daemon.NewClient(
+				&fakeRequester{body: tc.body}, tc.opts...,
+			)
+
+			_, err := client.GetTransactions(
+				context.Background(), []string{"abc"},
+			)
+			if tc.sentinel == nil && tc.status == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			if tc.sentinel != nil {
+				assert.True(t, errors.Is(err, tc.sentinel))
+			}
+
+			if tc.status != "" {
+				var statusErr *daemon.StatusError
+				require.True(t, errors.As(err, &statusErr))
+				assert.Equal(t, tc.status, statusErr.Status)
+			}
+		})
+	}
+}
+
+func TestClientGetBlocksBin(t *testing.T) {
+	t.Parallel()
+
+	var hash daemon.Hash
+	hash[0] = 0xaa
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/get_blocks.bin", r.URL.Path)
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		params := daemon.GetBlocksBinRequestParameters{}
+		require.NoError(t, epee.Unmarshal(body, &params))
+		assert.Equal(t, []daemon.Hash{hash}, params.BlockIDs)
+		assert.Equal(t, uint64(10), params.StartHeight)
+		assert.True(t, params.Prune)
+
+		resp, err := epee.Marshal(map[string]interface{}{
+			"blocks": []interface{}{
+				map[string]interface{}{
+					"pruned":       true,
+					"block":        "block",
+					"block_weight": uint64(42),
+					"txs": []interface{}{
+						map[string]interface{}{
+							"blob":          "tx",
+							"prunable_hash": string(hash[:]),
+						},
+					},
+				},
+			},
+			"start_height":   uint64(10),
+			"current_height": uint64(11),
+			"status":         "OK",
+		})
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	})
+
+	res, err := daemon.NewClient(client).GetBlocksBin(context.Background(),
+		daemon.GetBlocksBinRequestParameters{
+			BlockIDs:    []daemon.Hash{hash},
+			StartHeight: 10,
+			Prune:       true,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, res.Blocks, 1)
+	assert.Equal(t, []byte("block"), res.Blocks[0].Block)
+	assert.Equal(t, uint64(42), res.Blocks[0].BlockWeight)
+	require.Len(t, res.Blocks[0].Txs, 1)
+	assert.Equal(t, []byte("tx"), res.Blocks[0].Txs[0].Blob)
+	assert.Equal(t, hash, res.Blocks[0].Txs[0].PrunableHash)
+	assert.Equal(t, uint64(11), res.CurrentHeight)
+}
+
+func TestClientBinaryStatusCheck(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		resp, err := epee.Marshal(map[string]interface{}{
+			"status": "BUSY",
+		})
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	})
+
+	_, err := daemon.NewClient(client).GetBlocksByHeightBin(
+		context.Background(), []uint64{1, 2},
+	)
+	assert.True(t, errors.Is(err, daemon.ErrBusy))
+}
+
+func TestClientGetOutputDistributionBin(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/get_output_distribution.bin", r.URL.Path)
+
+		resp, err := epee.Marshal(map[string]interface{}{
+			"distributions": []interface{}{
+				map[string]interface{}{
+					"amount":       uint64(0),
+					"start_height": uint64(100),
+					"base":         uint64(7),
+					"binary":       true,
+					"compress":     true,
+					// 1, 300, 2 as LEB128 varints.
+					"compressed_data": "\x01\xac\x02\x02",
+				},
+			},
+			"status": "OK",
+		})
+		require.NoError(t, err)
+
+		_, _ = w.Write(resp)
+	})
+
+	res, err := daemon.NewClient(client).GetOutputDistributionBin(
+		context.Background(), daemon.GetOutputDistributionRequestParameters{
+			Amounts:  []uint64{0},
+			Binary:   true,
+			Compress: true,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, res.Distributions, 1)
+	assert.Equal(t, uint64(100), res.Distributions[0].StartHeight)
+	assert.Equal(t, uint64(7), res.Distributions[0].Base)
+	assert.Equal(t, []uint64{1, 300, 2}, res.Distributions[0].Distribution)
+}
+
+func TestClientGetOutputDistribution(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/json_rpc", r.URL.Path)
+
+		req := struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "get_output_distribution", req.Method)
+
+		// blobs can't go over JSON, so the distribution must not be
+		// asked for binary nor compressed.
+		assert.Equal(t, map[string]interface{}{
+			"amounts":     []interface{}{float64(0)},
+			"from_height": float64(100),
+			"cumulative":  true,
+			"binary":      false,
+		}, req.Params)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"0","result":{
+			"distributions":[{"amount":0,"start_height":100,"base":7,
+				"binary":false,"compress":false,
+				"distribution":[8,10,13]}],
+			"status":"OK"}}`))
+	})
+
+	res, err := daemon.NewClient(client).GetOutputDistribution(
+		context.Background(), daemon.GetOutputDistributionRequestParameters{
+			Amounts:    []uint64{0},
+			FromHeight: 100,
+			Cumulative: true,
+			Binary:     true,
+			Compress:   true,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, res.Distributions, 1)
+	assert.Equal(t, uint64(100), res.Distributions[0].StartHeight)
+	assert.Equal(t, uint64(7), res.Distributions[0].Base)
+	assert.Equal(t, []uint64{8, 10, 13}, res.Distributions[0].Distribution)
+}
+
+func TestClientGetOutputHistogram(t *testing.T) {
+	t.Parallel()
+
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		req := struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "get_output_histogram", req.Method)
+		assert.Equal(t, map[string]interface{}{
+			"amounts":   []interface{}{float64(20000000000)},
+			"min_count": float64(10),
+			"unlocked":  true,
+		}, req.Params)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"0","result":{
+			"histogram":[{"amount":20000000000,"total_instances":381490,
+				"unlocked_instances":0,"recent_instances":0}],
+			"status":"OK"}}`))
+	})
+
+	res, err := daemon.NewClient(client).GetOutputHistogram(
+		context.Background(), daemon.GetOutputHistogramRequestParameters{
+			Amounts:  []uint64{20000000000},
+			MinCount: 10,
+			Unlocked: true,
+		},
+	)
+	require.NoError(t, err)
+
+	require.Len(t, res.Histogram, 1)
+	assert.Equal(t, uint64(20000000000), res.Histogram[0].Amount)
+	assert.Equal(t, uint64(381490), res.Histogram[0].TotalInstances)
+}
+
+func TestClientSendRawTransactionRejected(t *testing.T) {
+	t.Parallel()
+
+	client := daemon.NewClient(&fakeRequester{
+		body: `{"status":"Failed","reason":"","double_spend":true,"fee_too_low":true}`,
+	})
+
+	_, err := client.SendRawTransaction(context.Background(),
+		daemon.SendRawTransactionRequestParameters{TxAsHex: "00"},
+	)
+	require.Error(t, err)
+
+	var rejectedErr *daemon.TxRejectedError
+	require.True(t, errors.As(err, &rejectedErr))
+	assert.Equal(t, []string{"double_spend", "fee_too_low"},
+		rejectedErr.Result.Reasons())
+
+	var statusErr *daemon.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, daemon.StatusFailed, statusErr.Status)
+}
+
+func TestClientIsKeyImageSpent(t *testing.T) {
+	t.Parallel()
+
+	client := daemon.NewClient(&fakeRequester{
+		body: `{"status":"OK","spent_status":[0,1,2]}`,
+	})
+
+	res, err := client.IsKeyImageSpent(context.Background(),
+		[]string{"a", "b", "c"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []daemon.KeyImageSpentStatus{
+		daemon.KeyImageUnspent,
+		daemon.KeyImageSpentInBlockchain,
+		daemon.KeyImageSpentInPool,
+	}, res.SpentStatus)
+	assert.Equal(t, "spent in pool", res.SpentStatus[2].String())
+}
+
+func TestClientGetTxPoolBacklog(t *testing.T) {
+	t.Parallel()
+
+	// weight=10 (\n), fee=0xff (raw byte), time_in_pool="\""
+	blob := "\\n\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000" +
+		"\xff\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000" +
+		"\\\"\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000\\u0000"
+
+	client := daemon.NewClient(&fakeRequester{
+		body: `{"status":"OK","backlog":"` + blob + `"}`,
+	})
+
+	res, err := client.GetTxPoolBacklog(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, daemon.TxBacklog{
+		{Weight: 10, Fee: 0xff, TimeInPool: '"'},
+	}, res.Backlog)
+}
+
+func TestGetTransactionPoolResultGetTransactions(t *testing.T) {
+	t.Parallel()
+
+	res := &daemon.GetTransactionPoolResult{
+		Transactions: []daemon.GetTransactionPoolResultTransaction{
+			{IDHash: "abc", TxJSON: `{"version":2,"unlock_time":0}`},
+		},
+	}
+
+	txns, err := res.GetTransactions()
+	require.NoError(t, err)
+	require.Len(t, txns, 1)
+	assert.Equal(t, 2, txns[0].Version)
+
+	res.Transactions[0].TxJSON = ""
+	_, err = res.GetTransactions()
+	assert.Error(t, err)
+}
+
+// adminEndpoint is a call to one of the endpoints for administering a node,
+// along with what's expected to go over the wire for it.
+type adminEndpoint struct {
+	name string
+
+	// path is the endpoint hit, and method, the JSONRPC method invoked
+	// when hitting `/json_rpc`.
+	path   string
+	method string
+
+	// restricted indicates that monerod doesn't serve the endpoint on a
+	// restricted RPC port.
+	restricted bool
+
+	params   interface{}
+	reply    string
+	call     func(ctx context.Context, c *daemon.Client) (interface{}, error)
+	expected interface{}
+}
+
+func adminEndpoints() []adminEndpoint {
+	ok := daemon.RPCResultFooter{Status: daemon.StatusOK}
+	no := false
+
+	bans := &daemon.GetBansResult{RPCResultFooter: ok}
+	bans.Bans = append(bans.Bans, struct {
+		Host    string `json:"host"`
+		IP      int    `json:"ip"`
+		Seconds uint   `json:"seconds"`
+	}{Host: "192.168.1.51", IP: 855746752, Seconds: 3600})
+
+	return []adminEndpoint{
+		{
+			name:       "save_bc",
+			restricted: true,
+			path:       "/save_bc",
+			reply:      `{"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.SaveBC(ctx)
+			},
+			expected: &daemon.SaveBCResult{RPCResultFooter: ok},
+		},
+		{
+			name:       "pop_blocks",
+			restricted: true,
+			path:       "/pop_blocks",
+			params:     map[string]interface{}{"nblocks": float64(2)},
+			reply:      `{"height":2812340,"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.PopBlocks(ctx, 2)
+			},
+			expected: &daemon.PopBlocksResult{
+				Height: 2812340, RPCResultFooter: ok,
+			},
+		},
+		{
+			name:       "out_peers",
+			restricted: true,
+			path:       "/out_peers",
+			params: map[string]interface{}{
+				"set": false, "out_peers": float64(8),
+			},
+			reply: `{"out_peers":12,"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.OutPeers(ctx, daemon.OutPeersRequestParameters{
+					Set: &no, OutPeers: 8,
+				})
+			},
+			expected: &daemon.OutPeersResult{
+				OutPeers: 12, RPCResultFooter: ok,
+			},
+		},
+		{
+			name:       "in_peers",
+			restricted: true,
+			path:       "/in_peers",
+			params:     map[string]interface{}{"in_peers": float64(16)},
+			reply:      `{"in_peers":16,"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.InPeers(ctx, daemon.InPeersRequestParameters{
+					InPeers: 16,
+				})
+			},
+			expected: &daemon.InPeersResult{
+				InPeers: 16, RPCResultFooter: ok,
+			},
+		},
+		{
+			name:       "get_bans",
+			restricted: true,
+			path:       "/json_rpc",
+			method:     "get_bans",
+			reply: `{"bans":[{"host":"192.168.1.51","ip":855746752,
+				"seconds":3600}],"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.GetBans(ctx)
+			},
+			expected: bans,
+		},
+		{
+			name:       "set_bans",
+			restricted: true,
+			path:       "/json_rpc",
+			method:     "set_bans",
+			params: map[string]interface{}{
+				"bans": []interface{}{
+					map[string]interface{}{
+						"host":    "192.168.1.51",
+						"ban":     true,
+						"seconds": float64(3600),
+					},
+				},
+			},
+			reply: `{"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.SetBans(ctx, daemon.SetBansRequestParameters{
+					Bans: []daemon.SetBansBan{
+						{Host: "192.168.1.51", Ban: true, Seconds: 3600},
+					},
+				})
+			},
+			expected: &daemon.SetBansResult{RPCResultFooter: ok},
+		},
+		{
+			name:  "get_limit",
+			path:  "/get_limit",
+			reply: `{"limit_down":8192,"limit_up":2048,"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.GetLimit(ctx)
+			},
+			expected: &daemon.GetLimitResult{
+				LimitUp: 2048, LimitDown: 8192, RPCResultFooter: ok,
+			},
+		},
+		{
+			name:       "set_limit",
+			restricted: true,
+			path:       "/set_limit",
+			params: map[string]interface{}{
+				"limit_up": float64(1024), "limit_down": float64(4096),
+			},
+			reply: `{"limit_down":4096,"limit_up":1024,"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.SetLimit(ctx, daemon.SetLimitRequestParameters{
+					LimitUp: 1024, LimitDown: 4096,
+				})
+			},
+			expected: &daemon.SetLimitResult{
+				LimitUp: 1024, LimitDown: 4096, RPCResultFooter: ok,
+			},
+		},
+		{
+			name:       "set_log_level",
+			restricted: true,
+			path:       "/set_log_level",
+			params:     map[string]interface{}{"level": float64(1)},
+			reply:      `{"status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.SetLogLevel(ctx, daemon.SetLogLevelRequestParameters{
+					Level: 1,
+				})
+			},
+			expected: &daemon.SetLogLevelResult{RPCResultFooter: ok},
+		},
+		{
+			name:       "set_log_categories",
+			restricted: true,
+			path:       "/set_log_categories",
+			params:     map[string]interface{}{"categories": "net.http:1"},
+			reply:      `{"categories":"net.http:1","status":"OK"}`,
+			call: func(ctx context.Context, c *daemon.Client) (interface{}, error) {
+				return c.SetLogCategories(ctx,
+					daemon.SetLogCategoriesRequestParameters{
+						Categories: "net.http:1",
+					},
+				)
+			},
+			expected: &daemon.SetLogCategoriesResult{
+				Categories: "net.http:1", RPCResultFooter: ok,
+			},
+		},
+	}
+}
+
+func TestClientAdminEndpoints(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range adminEndpoints() {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, tc.path, r.URL.Path)
+
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+
+				var params interface{}
+
+				if tc.method == "" {
+					if len(body) > 0 {
+						require.NoError(t, json.Unmarshal(body, &params))
+					}
+
+					assert.Equal(t, tc.params, params)
+					_, _ = w.Write([]byte(tc.reply))
+
+					return
+				}
+
+				req := struct {
+					Method string      `json:"method"`
+					Params interface{} `json:"params"`
+				}{}
+				require.NoError(t, json.Unmarshal(body, &req))
+				assert.Equal(t, tc.method, req.Method)
+				assert.Equal(t, tc.params, req.Params)
+
+				_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"0",` +
+					`"result":` + tc.reply + `}`))
+			})
+
+			res, err := tc.call(context.Background(), daemon.NewClient(client))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
+func TestClientAdminEndpointsRestricted(t *testing.T) {
+	t.Parallel()
+
+	// a node serving a restricted RPC port doesn't register any of these,
+	// thus replying with a 404 for raw endpoints, and "method not found"
+	// for JSONRPC methods.
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/json_rpc" {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":"0","error":` +
+			`{"code":-32601,"message":"Method not found"}}`))
+	})
+
+	for _, tc := range adminEndpoints() {
+		tc := tc
+
+		if !tc.restricted {
+			continue
+		}
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := tc.call(context.Background(), daemon.NewClient(client))
+			require.Error(t, err)
+			assert.True(t, daemon.IsRestricted(err))
+		})
+	}
+}
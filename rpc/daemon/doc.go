@@ -1,4 +1,22 @@
 // Package daemon provides a client that encapsulates RPC methods and endpoints
 // that can be hit in a daemon.
 //
+// `PaymentRequester` pays for the requests made to a daemon that requires
+// payment (`--rpc-payment-address`), mining for credits with a `Hasher`.
+//
+// This package does not implement either of the two pieces of cryptography
+// that this requires, as it carries no Monero cryptography of its own:
+//
+//   - the `Signer`, producing the `client` field: the hex-encoded public
+//     key of a keypair that the client sticks to, followed by the current
+//     time in microseconds as 16 hex digits, and a Monero signature
+//     (`crypto::generate_signature`) of the Keccak-256 hash of those 16
+//     digits, as done by `make_rpc_payment_signature` in monero's
+//     `src/rpc/rpc_payment_signature.cpp`;
+//
+//   - the `Hasher`, computing the RandomX hash of the hashing blobs.
+//
+// Both must be provided by the caller, e.g., through bindings to monero's
+// libraries or a Go implementation of ed25519 and RandomX.
+//
 package daemon
@@ -0,0 +1,240 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/duggavo/go-monero/rpc"
+)
+
+// Error codes that `monerod` fills JSONRPC error objects with (see
+// `core_rpc_server_error_codes.h`).
+const (
+	ErrorCodeWrongParam           = -1
+	ErrorCodeTooBigHeight         = -2
+	ErrorCodeTooBigReserveSize    = -3
+	ErrorCodeWrongWalletAddress   = -4
+	ErrorCodeInternalError        = -5
+	ErrorCodeWrongBlockblob       = -6
+	ErrorCodeBlockNotAccepted     = -7
+	ErrorCodeCoreBusy             = -9
+	ErrorCodeWrongBlockblobSize   = -10
+	ErrorCodeUnsupportedRPC       = -11
+	ErrorCodeMiningToSubaddress   = -12
+	ErrorCodeRegtestRequired      = -13
+	ErrorCodePaymentRequired      = -14
+	ErrorCodeInvalidClient        = -15
+	ErrorCodePaymentTooLow        = -16
+	ErrorCodeDuplicatePayment     = -17
+	ErrorCodeStalePayment         = -18
+	ErrorCodeRestricted           = -19
+	ErrorCodeUnsupportedBootstrap = -20
+	ErrorCodePaymentNotRequired   = -21
+)
+
+// Values that `monerod` fills the `status` field of results with (see
+// `core_rpc_server_commands_defs.h`).
+const (
+	StatusOK              = "OK"
+	StatusBusy            = "BUSY"
+	StatusNotMining       = "NOT MINING"
+	StatusPaymentRequired = "PAYMENT REQUIRED"
+	StatusFailed          = "Failed"
+)
+
+// Sentinels matching the errors that `monerod` replies with, i.e., for use
+// with `errors.Is`, which reports true for any `*rpc.RPCError` with the
+// same code.
+var (
+	// ErrWrongParam matches `ErrorCodeWrongParam`.
+	ErrWrongParam = &rpc.RPCError{
+		Code: ErrorCodeWrongParam, Message: "wrong param",
+	}
+
+	// ErrTooBigHeight matches `ErrorCodeTooBigHeight`.
+	ErrTooBigHeight = &rpc.RPCError{
+		Code: ErrorCodeTooBigHeight, Message: "too big height",
+	}
+
+	// ErrTooBigReserveSize matches `ErrorCodeTooBigReserveSize`.
+	ErrTooBigReserveSize = &rpc.RPCError{
+		Code: ErrorCodeTooBigReserveSize, Message: "too big reserve size",
+	}
+
+	// ErrWrongWalletAddress matches `ErrorCodeWrongWalletAddress`.
+	ErrWrongWalletAddress = &rpc.RPCError{
+		Code: ErrorCodeWrongWalletAddress, Message: "wrong wallet address",
+	}
+
+	// ErrInternalError matches `ErrorCodeInternalError`.
+	ErrInternalError = &rpc.RPCError{
+		Code: ErrorCodeInternalError, Message: "internal error",
+	}
+
+	// ErrWrongBlockblob matches `ErrorCodeWrongBlockblob`.
+	ErrWrongBlockblob = &rpc.RPCError{
+		Code: ErrorCodeWrongBlockblob, Message: "wrong block blob",
+	}
+
+	// ErrBlockNotAccepted matches `ErrorCodeBlockNotAccepted`.
+	ErrBlockNotAccepted = &rpc.RPCError{
+		Code: ErrorCodeBlockNotAccepted, Message: "block not accepted",
+	}
+
+	// ErrWrongBlockblobSize matches `ErrorCodeWrongBlockblobSize`.
+	ErrWrongBlockblobSize = &rpc.RPCError{
+		Code: ErrorCodeWrongBlockblobSize, Message: "wrong block blob size",
+	}
+
+	// ErrUnsupportedRPC matches `ErrorCodeUnsupportedRPC`.
+	ErrUnsupportedRPC = &rpc.RPCError{
+		Code: ErrorCodeUnsupportedRPC, Message: "unsupported rpc",
+	}
+
+	// ErrMiningToSubaddress matches `ErrorCodeMiningToSubaddress`.
+	ErrMiningToSubaddress = &rpc.RPCError{
+		Code: ErrorCodeMiningToSubaddress, Message: "mining to subaddress",
+	}
+
+	// ErrRegtestRequired matches `ErrorCodeRegtestRequired`.
+	ErrRegtestRequired = &rpc.RPCError{
+		Code: ErrorCodeRegtestRequired, Message: "regtest required",
+	}
+
+	// ErrInvalidClient matches `ErrorCodeInvalidClient`.
+	ErrInvalidClient = &rpc.RPCError{
+		Code: ErrorCodeInvalidClient, Message: "invalid client",
+	}
+
+	// ErrPaymentTooLow matches `ErrorCodePaymentTooLow`.
+	ErrPaymentTooLow = &rpc.RPCError{
+		Code: ErrorCodePaymentTooLow, Message: "payment too low",
+	}
+
+	// ErrDuplicatePayment matches `ErrorCodeDuplicatePayment`.
+	ErrDuplicatePayment = &rpc.RPCError{
+		Code: ErrorCodeDuplicatePayment, Message: "duplicate payment",
+	}
+
+	// ErrStalePayment matches `ErrorCodeStalePayment`.
+	ErrStalePayment = &rpc.RPCError{
+		Code: ErrorCodeStalePayment, Message: "stale payment",
+	}
+
+	// ErrUnsupportedBootstrap matches `ErrorCodeUnsupportedBootstrap`.
+	ErrUnsupportedBootstrap = &rpc.RPCError{
+		Code: ErrorCodeUnsupportedBootstrap, Message: "unsupported bootstrap",
+	}
+
+	// ErrPaymentNotRequired matches `ErrorCodePaymentNotRequired`.
+	ErrPaymentNotRequired = &rpc.RPCError{
+		Code: ErrorCodePaymentNotRequired, Message: "payment not required",
+	}
+
+	// ErrBusy matches errors from a daemon that is too busy (e.g., still
+	// syncing) to serve the request.
+	//
+	ErrBusy = &rpc.RPCError{
+		Code: ErrorCodeCoreBusy, Message: "core is busy",
+	}
+
+	// ErrPaymentRequired matches errors from a daemon that requires
+	// payment for serving RPC requests.
+	//
+	ErrPaymentRequired = &rpc.RPCError{
+		Code: ErrorCodePaymentRequired, Message: "payment required",
+	}
+
+	// ErrRestricted matches errors from a daemon refusing to serve a
+	// request due to it being exposed in restricted mode.
+	//
+	// ps.: `monerod` doesn't register restricted methods at all when
+	// restricted, so most of the time the denial comes in the form of
+	// `rpc.ErrMethodNotFound` or `rpc.ErrNotFound` instead - see
+	// `IsRestricted`.
+	//
+	ErrRestricted = &rpc.RPCError{
+		Code: ErrorCodeRestricted, Message: "restricted",
+	}
+)
+
+// ErrBinaryUnsupported is the error returned when hitting a binary endpoint
+// through a client whose requester doesn't implement `BinaryRequester`.
+var ErrBinaryUnsupported = errors.New("requester doesn't support binary requests")
+
+// ErrUntrusted is the error returned by a client configured with
+// `WithRejectUntrusted` when the daemon replies with a result obtained from a
+// bootstrap daemon.
+var ErrUntrusted = errors.New("untrusted result from bootstrap daemon")
+
+// StatusError is the error returned when the daemon fills the `status` field
+// of a result with anything other than `StatusOK`.
+//
+// `errors.Is` reports true for `ErrBusy` and `ErrPaymentRequired` when the
+// status is `StatusBusy` and `StatusPaymentRequired`, respectively.
+type StatusError struct {
+	// Status is the raw status as sent by the daemon.
+	//
+	Status string
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status not ok: %q", e.Status)
+}
+
+// Retryable reports whether the request is worth retrying, i.e., the daemon
+// was only too busy to serve it.
+func (e *StatusError) Retryable() bool {
+	return e.Status == StatusBusy
+}
+
+// Is reports whether `target` corresponds to the status of this error.
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrBusy:
+		return e.Status == StatusBusy
+	case ErrPaymentRequired:
+		return e.Status == StatusPaymentRequired
+	}
+
+	return false
+}
+
+// IsRestricted reports whether `err` denotes a denial to serve a method or
+// endpoint that the node only exposes when not serving in restricted mode.
+func IsRestricted(err error) bool {
+	return errors.Is(err, ErrRestricted) ||
+		errors.Is(err, rpc.ErrMethodNotFound) ||
+		errors.Is(err, rpc.ErrNotFound) ||
+		errors.Is(err, rpc.ErrForbidden)
+}
+
+// TxRejectedError is the error returned by SendRawTransaction when the daemon
+// refuses to accept a transaction.
+//
+// It wraps the `*StatusError` from the status check, so the raw status is
+// still reachable via `errors.As`.
+type TxRejectedError struct {
+	// Result is the full result sent back by the daemon, carrying every
+	// rejection flag - see `Reasons`.
+	//
+	Result *SendRawTransactionResult
+
+	err error
+}
+
+// Error implements the error interface.
+func (e *TxRejectedError) Error() string {
+	msg := fmt.Sprintf("tx rejected: reasons=%v", e.Result.Reasons())
+	if e.Result.Reason != "" {
+		msg += fmt.Sprintf(" reason=%q", e.Result.Reason)
+	}
+
+	return msg
+}
+
+// Unwrap returns the underlying status error.
+func (e *TxRejectedError) Unwrap() error {
+	return e.err
+}
@@ -39,6 +39,115 @@ func (c *Client) RPCAccessTracking(
 	return resp, nil
 }
 
+// ClearRPCAccessTracking resets the statistics that the monero daemon keeps
+// track of about the use of each RPC method and endpoint.
+//
+// (restricted).
+func (c *Client) ClearRPCAccessTracking(
+	ctx context.Context,
+) (*RPCAccessTrackingResult, error) {
+	resp := &RPCAccessTrackingResult{}
+	params := map[string]interface{}{
+		"clear": true,
+	}
+
+	err := c.JSONRPC(ctx, "rpc_access_tracking", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessInfo retrieves the job that a paying client should mine on to
+// earn credits, along with its current balance.
+//
+// `client` is the signature identifying the paying client (see `Signer`) -
+// it can be left empty when going through a `PaymentRequester`.
+func (c *Client) RPCAccessInfo(
+	ctx context.Context, client string,
+) (*RPCAccessInfoResult, error) {
+	resp := &RPCAccessInfoResult{}
+	params := map[string]interface{}{}
+
+	if client != "" {
+		params["client"] = client
+	}
+
+	err := c.JSONRPC(ctx, "rpc_access_info", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessSubmitNonce submits a nonce found for a job obtained via
+// RPCAccessInfo, crediting the client.
+func (c *Client) RPCAccessSubmitNonce(
+	ctx context.Context, params RPCAccessSubmitNonceRequestParameters,
+) (*RPCAccessSubmitNonceResult, error) {
+	resp := &RPCAccessSubmitNonceResult{}
+
+	err := c.JSONRPC(ctx, "rpc_access_submit_nonce", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessPay pays for a service with credits.
+func (c *Client) RPCAccessPay(
+	ctx context.Context, params RPCAccessPayRequestParameters,
+) (*RPCAccessPayResult, error) {
+	resp := &RPCAccessPayResult{}
+
+	err := c.JSONRPC(ctx, "rpc_access_pay", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessData retrieves the accounting of every paying client.
+//
+// (restricted).
+func (c *Client) RPCAccessData(
+	ctx context.Context,
+) (*RPCAccessDataResult, error) {
+	resp := &RPCAccessDataResult{}
+
+	err := c.JSONRPC(ctx, "rpc_access_data", nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// RPCAccessAccount adjusts the balance of a paying client by
+// `deltaBalance` credits, sending back the resulting balance.
+//
+// (restricted).
+func (c *Client) RPCAccessAccount(
+	ctx context.Context, client string, deltaBalance int64,
+) (*RPCAccessAccountResult, error) {
+	resp := &RPCAccessAccountResult{}
+	params := map[string]interface{}{
+		"client":        client,
+		"delta_balance": deltaBalance,
+	}
+
+	err := c.JSONRPC(ctx, "rpc_access_account", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
 // HardForkInfo looks up informaiton about the last hard fork.
 func (c *Client) HardForkInfo(
 	ctx context.Context,
@@ -67,12 +176,22 @@ func (c *Client) GetBans(ctx context.Context) (*GetBansResult, error) {
 	return resp, nil
 }
 
+// SetBansBan is the ban (or unban) of a host, as passed to the SetBans
+// method.
 type SetBansBan struct {
-	Host    string `json:"host"`
-	Ban     bool   `json:"ban"`
-	Seconds int64  `json:"seconds"`
+	// Host is the host (IP) to ban or unban.
+	Host string `json:"host"`
+
+	// Ban indicates whether the host should be banned (otherwise, it's
+	// unbanned).
+	Ban bool `json:"ban"`
+
+	// Seconds is the duration of the ban.
+	Seconds int64 `json:"seconds"`
 }
 
+// SetBansRequestParameters is the set of parameters to be passed to the
+// SetBans method.
 type SetBansRequestParameters struct {
 	Bans []SetBansBan `json:"bans"`
 }
@@ -390,3 +509,113 @@ func (c *Client) GetMinerData(ctx context.Context) (*GetMinerDataResult, error)
 
 	return resp, nil
 }
+
+// GetOutputDistribution retrieves the distribution of outputs of each of the
+// amounts specified over a range of blocks, e.g., for picking decoys.
+func (c *Client) GetOutputDistribution(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionResult, error) {
+	resp := &GetOutputDistributionResult{}
+
+	params.Binary = false
+	params.Compress = false
+
+	err := c.JSONRPC(ctx, "get_output_distribution", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputHistogram retrieves, for each amount, the number of outputs in
+// the chain.
+func (c *Client) GetOutputHistogram(
+	ctx context.Context, params GetOutputHistogramRequestParameters,
+) (*GetOutputHistogramResult, error) {
+	resp := &GetOutputHistogramResult{}
+
+	err := c.JSONRPC(ctx, "get_output_histogram", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTxPoolBacklog retrieves the weight, fee and time in the pool of each of
+// the transactions in the pool.
+func (c *Client) GetTxPoolBacklog(
+	ctx context.Context,
+) (*GetTxPoolBacklogResult, error) {
+	resp := &GetTxPoolBacklogResult{}
+
+	err := c.JSONRPC(ctx, "get_txpool_backlog", nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FlushTxPool removes the transactions with the given IDs from the pool, or
+// every transaction if none is given.
+//
+// (restricted).
+func (c *Client) FlushTxPool(
+	ctx context.Context, txids []string,
+) (*FlushTxPoolResult, error) {
+	resp := &FlushTxPoolResult{}
+	params := map[string]interface{}{}
+
+	if len(txids) > 0 {
+		params["txids"] = txids
+	}
+
+	err := c.JSONRPC(ctx, "flush_txpool", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PruneBlockchain prunes the blockchain, or only checks whether it's pruned
+// if `check` is true.
+//
+// (restricted).
+func (c *Client) PruneBlockchain(
+	ctx context.Context, check bool,
+) (*PruneBlockchainResult, error) {
+	resp := &PruneBlockchainResult{}
+	params := map[string]interface{}{
+		"check": check,
+	}
+
+	err := c.JSONRPC(ctx, "prune_blockchain", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FlushCache flushes the caches of bad transactions and/or blocks.
+//
+// (restricted).
+func (c *Client) FlushCache(
+	ctx context.Context, badTxs, badBlocks bool,
+) (*FlushCacheResult, error) {
+	resp := &FlushCacheResult{}
+	params := map[string]interface{}{
+		"bad_txs":    badTxs,
+		"bad_blocks": badBlocks,
+	}
+
+	err := c.JSONRPC(ctx, "flush_cache", params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,492 @@
+package daemon
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/duggavo/go-monero/epee"
+)
+
+// PaymentNonceOffset is the offset in a hashing blob (see `RPCAccessInfo`) at
+// which the nonce goes, encoded as a little-endian uint32.
+const PaymentNonceOffset = 39
+
+// paymentHashesPerJob is the number of nonces tried against a single job
+// before fetching a fresh one, so that we don't keep mining on a stale blob
+// once the chain has moved on.
+const paymentHashesPerJob = 4096
+
+// defaultMaxMiningJobs is the number of jobs that `Mine` goes through before
+// giving up, unless overridden with `WithMaxMiningJobs`.
+const defaultMaxMiningJobs = 64
+
+// ErrMiningExhausted is the error returned by `Mine` (and thus by requests
+// needing credits) when the balance still doesn't reach the target after
+// going through the maximum number of jobs.
+//
+var ErrMiningExhausted = errors.New("mining jobs exhausted")
+
+// Signer produces the value of the `client` field identifying a client that
+// pays for RPC requests, i.e., the hex-encoded public key followed by a
+// timestamp and a signature of it (see `make_rpc_payment_signature` in
+// monero's `rpc_payment_signature.cpp`).
+//
+// ps.: no implementation is provided by this package - see the package
+// documentation.
+//
+type Signer interface {
+	Sign() (string, error)
+}
+
+// SignerFunc is an adapter for using a plain function as a Signer.
+//
+type SignerFunc func() (string, error)
+
+// Sign implements Signer.
+func (f SignerFunc) Sign() (string, error) {
+	return f()
+}
+
+// Hasher computes the proof-of-work hash of a hashing blob from a job
+// obtained via `RPCAccessInfo` (e.g., RandomX keyed with `info.SeedHash`).
+//
+type Hasher interface {
+	Hash(blob []byte, info *RPCAccessInfoResult) (Hash, error)
+}
+
+// CheckHash reports whether `hash`, taken as a little-endian 256-bit
+// integer, meets `difficulty`, i.e., `hash * difficulty < 2^256`.
+func CheckHash(hash Hash, difficulty *big.Int) bool {
+	be := make([]byte, len(hash))
+	for i, b := range hash {
+		be[len(hash)-1-i] = b
+	}
+
+	product := new(big.Int).Mul(new(big.Int).SetBytes(be), difficulty)
+
+	return product.BitLen() <= 256
+}
+
+// PaymentRequester is a Requester that pays for the requests it makes to a
+// daemon that requires payment for serving RPC, i.e., it attaches the
+// signature of the client to every request, keeps track of the credits left
+// and, when configured with a Hasher, mines for credits whenever they run
+// low or the daemon refuses to serve a request due to lack of payment.
+//
+type PaymentRequester struct {
+	Requester
+
+	signer     Signer
+	hasher     Hasher
+	minCredits uint64
+	maxJobs    int
+
+	// miningMu ensures that only one goroutine mines at a time.
+	//
+	miningMu sync.Mutex
+
+	// mu guards `credits` and `nonce`.
+	//
+	mu      sync.Mutex
+	credits uint64
+	nonce   uint32
+}
+
+// paymentOptions is a set of options that can be overridden to tweak the
+// payment requester's behavior.
+//
+type paymentOptions struct {
+	Hasher        Hasher
+	MinCredits    uint64
+	MaxMiningJobs int
+}
+
+// PaymentOption defines a functional option for overriding optional payment
+// requester configuration parameters.
+//
+type PaymentOption func(o *paymentOptions)
+
+// WithHasher is a functional option for providing the hasher used for mining
+// for credits. Without one, the requester never mines, only paying with
+// credits obtained by other means.
+//
+func WithHasher(v Hasher) func(o *paymentOptions) {
+	return func(o *paymentOptions) {
+		o.Hasher = v
+	}
+}
+
+// WithMinCredits is a functional option for setting the number of credits
+// below which the requester mines before making a request (requires
+// `WithHasher`).
+//
+func WithMinCredits(v uint64) func(o *paymentOptions) {
+	return func(o *paymentOptions) {
+		o.MinCredits = v
+	}
+}
+
+// WithMaxMiningJobs is a functional option for setting the maximum number of
+// jobs (each of which tries a few thousand nonces) that mining goes through
+// before giving up with `ErrMiningExhausted`, bounding the time that a
+// request may spend mining regardless of the deadline of its context.
+//
+func WithMaxMiningJobs(v int) func(o *paymentOptions) {
+	return func(o *paymentOptions) {
+		o.MaxMiningJobs = v
+	}
+}
+
+// NewPaymentRequester instantiates a PaymentRequester making requests via
+// `r`, identifying the client with signatures from `signer`.
+//
+func NewPaymentRequester(
+	r Requester, signer Signer, opts ...PaymentOption,
+) *PaymentRequester {
+	options := &paymentOptions{
+		MaxMiningJobs: defaultMaxMiningJobs,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &PaymentRequester{
+		Requester:  r,
+		signer:     signer,
+		hasher:     options.Hasher,
+		minCredits: options.MinCredits,
+		maxJobs:    options.MaxMiningJobs,
+	}
+}
+
+// Credits returns the number of credits left as last reported by the daemon.
+//
+func (p *PaymentRequester) Credits() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.credits
+}
+
+// JSONRPC calls `method` with the client signature attached to `params`.
+//
+func (p *PaymentRequester) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return p.pay(ctx, result, func() error {
+		signed, err := p.signJSON(params)
+		if err != nil {
+			return err
+		}
+
+		return p.Requester.JSONRPC(ctx, method, signed, result)
+	})
+}
+
+// RawRequest hits `endpoint` with the client signature attached to
+// `params`.
+//
+func (p *PaymentRequester) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.pay(ctx, response, func() error {
+		signed, err := p.signJSON(params)
+		if err != nil {
+			return err
+		}
+
+		return p.Requester.RawRequest(ctx, endpoint, signed, response)
+	})
+}
+
+// BinaryRequest hits the binary endpoint `endpoint` with the client
+// signature attached to `params`.
+//
+func (p *PaymentRequester) BinaryRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	requester, ok := p.Requester.(BinaryRequester)
+	if !ok {
+		return ErrBinaryUnsupported
+	}
+
+	return p.pay(ctx, response, func() error {
+		signed, err := p.signBinary(params)
+		if err != nil {
+			return err
+		}
+
+		return requester.BinaryRequest(ctx, endpoint, signed, response)
+	})
+}
+
+// Mine mines for credits, submitting the nonces found, until the balance
+// reaches `target`, failing with `ErrMiningExhausted` if it doesn't within
+// the maximum number of jobs (see `WithMaxMiningJobs`).
+//
+func (p *PaymentRequester) Mine(ctx context.Context, target uint64) error {
+	if p.hasher == nil {
+		return fmt.Errorf("no hasher configured")
+	}
+
+	p.miningMu.Lock()
+	defer p.miningMu.Unlock()
+
+	client := &Client{Requester: p.Requester}
+
+	for jobs := 0; ; jobs++ {
+		signature, err := p.signer.Sign()
+		if err != nil {
+			return fmt.Errorf("sign: %w", err)
+		}
+
+		info, err := client.RPCAccessInfo(ctx, signature)
+		if err != nil {
+			return fmt.Errorf("rpc access info: %w", err)
+		}
+
+		p.track(info)
+
+		if info.Credits >= target {
+			return nil
+		}
+
+		if jobs >= p.maxJobs {
+			return fmt.Errorf("%w: %d of %d credits after %d jobs",
+				ErrMiningExhausted, info.Credits, target, jobs)
+		}
+
+		nonce, found, err := p.search(ctx, info)
+		if err != nil {
+			return fmt.Errorf("search: %w", err)
+		}
+
+		if !found {
+			continue
+		}
+
+		signature, err = p.signer.Sign()
+		if err != nil {
+			return fmt.Errorf("sign: %w", err)
+		}
+
+		res, err := client.RPCAccessSubmitNonce(ctx,
+			RPCAccessSubmitNonceRequestParameters{
+				Client: signature,
+				Nonce:  nonce,
+				Cookie: info.Cookie,
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("rpc access submit nonce: %w", err)
+		}
+
+		p.track(res)
+	}
+}
+
+// search looks for a nonce that makes the hash of the hashing blob of the
+// job meet its difficulty, giving up (without an error) after
+// `paymentHashesPerJob` attempts.
+//
+func (p *PaymentRequester) search(
+	ctx context.Context, info *RPCAccessInfoResult,
+) (uint32, bool, error) {
+	blob, err := hex.DecodeString(info.HashingBlob)
+	if err != nil {
+		return 0, false, fmt.Errorf("decode hashing blob: %w", err)
+	}
+
+	if len(blob) < PaymentNonceOffset+4 {
+		return 0, false, fmt.Errorf("hashing blob too short: %d bytes",
+			len(blob))
+	}
+
+	difficulty, err := info.Difficulty()
+	if err != nil {
+		return 0, false, fmt.Errorf("difficulty: %w", err)
+	}
+
+	for i := 0; i < paymentHashesPerJob; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+
+		nonce := p.nextNonce()
+		binary.LittleEndian.PutUint32(blob[PaymentNonceOffset:], nonce)
+
+		hash, err := p.hasher.Hash(blob, info)
+		if err != nil {
+			return 0, false, fmt.Errorf("hash: %w", err)
+		}
+
+		if CheckHash(hash, difficulty) {
+			return nonce, true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+// pay performs a request via `do`, mining beforehand if the balance is known
+// to be low, and mining then retrying once if the daemon refuses to serve it
+// due to lack of payment.
+//
+func (p *PaymentRequester) pay(
+	ctx context.Context, result interface{}, do func() error,
+) error {
+	if p.hasher != nil && p.Credits() < p.minCredits {
+		if err := p.Mine(ctx, p.minCredits); err != nil {
+			return fmt.Errorf("mine: %w", err)
+		}
+	}
+
+	err := do()
+	p.track(result)
+
+	if p.hasher == nil || !paymentRequired(err, result) {
+		return err
+	}
+
+	target := p.minCredits
+	if target == 0 {
+		target = 1
+	}
+
+	if err := p.Mine(ctx, target); err != nil {
+		return fmt.Errorf("mine: %w", err)
+	}
+
+	err = do()
+	p.track(result)
+
+	return err
+}
+
+// track records the balance reported in the footer of `result`, if any.
+//
+func (p *PaymentRequester) track(result interface{}) {
+	footered, ok := result.(interface {
+		Footer() *RPCResultFooter
+	})
+	if !ok {
+		return
+	}
+
+	// the daemon only fills `top_hash` for paying clients, so that's what
+	// tells a zero balance apart from no balance reported at all.
+	footer := footered.Footer()
+	if footer.TopHash == "" && footer.Credits == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.credits = footer.Credits
+}
+
+// nextNonce gives the next nonce to try, unique for this requester.
+//
+func (p *PaymentRequester) nextNonce() uint32 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nonce++
+
+	return p.nonce
+}
+
+// signJSON attaches the client signature to JSON `params` that are either
+// empty or an object, leaving any other kind of params (e.g., positional
+// ones) untouched, as well as those already carrying a signature.
+//
+func (p *PaymentRequester) signJSON(params interface{}) (interface{}, error) {
+	fields := map[string]json.RawMessage{}
+
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("marshal: %w", err)
+		}
+
+		if err := json.Unmarshal(b, &fields); err != nil {
+			return params, nil
+		}
+	}
+
+	if client, ok := fields["client"]; ok && string(client) != `""` {
+		return fields, nil
+	}
+
+	signature, err := p.signer.Sign()
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	fields["client"], err = json.Marshal(signature)
+	if err != nil {
+		return nil, fmt.Errorf("marshal signature: %w", err)
+	}
+
+	return fields, nil
+}
+
+// signBinary attaches the client signature to the `params` of a binary
+// request.
+//
+func (p *PaymentRequester) signBinary(params interface{}) (interface{}, error) {
+	if params == nil {
+		params = struct{}{}
+	}
+
+	b, err := epee.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+
+	var decoded interface{}
+	if err := epee.Unmarshal(b, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	fields, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected params of type %T", decoded)
+	}
+
+	if client, _ := fields["client"].(string); client != "" {
+		return fields, nil
+	}
+
+	signature, err := p.signer.Sign()
+	if err != nil {
+		return nil, fmt.Errorf("sign: %w", err)
+	}
+
+	fields["client"] = signature
+
+	return fields, nil
+}
+
+// paymentRequired reports whether a request failed due to lack of payment,
+// be it through a JSONRPC error or the status of the result.
+//
+func paymentRequired(err error, result interface{}) bool {
+	if errors.Is(err, ErrPaymentRequired) {
+		return true
+	}
+
+	footered, ok := result.(interface {
+		Footer() *RPCResultFooter
+	})
+
+	return ok && footered.Footer().Status == StatusPaymentRequired
+}
@@ -0,0 +1,186 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/rpc/daemon"
+)
+
+// payingDaemon mimics a daemon requiring payment: it only serves clients
+// with credits, charging one per request, and credits ten per nonce.
+type payingDaemon struct {
+	credits uint64
+	clients []string
+	nonces  []uint32
+	cookies []uint32
+}
+
+func (d *payingDaemon) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	client, _ := fields["client"].(string)
+	d.clients = append(d.clients, client)
+
+	var body string
+
+	switch method {
+	case "rpc_access_info":
+		body = `{"status":"OK","top_hash":"aa","credits":` +
+			jsonUint(d.credits) + `,"cookie":7,"diff":100,` +
+			`"hashing_blob":"` + zeros(152) + `"}`
+	case "rpc_access_submit_nonce":
+		d.cookies = append(d.cookies, uint32(fields["cookie"].(float64)))
+		d.nonces = append(d.nonces, uint32(fields["nonce"].(float64)))
+		d.credits += 10
+		body = `{"status":"OK","top_hash":"aa","credits":` +
+			jsonUint(d.credits) + `}`
+	default:
+		if d.credits == 0 {
+			body = `{"status":"PAYMENT REQUIRED","top_hash":"aa"}`
+			break
+		}
+
+		d.credits--
+		body = `{"status":"OK","top_hash":"aa","credits":` +
+			jsonUint(d.credits) + `}`
+	}
+
+	return json.Unmarshal([]byte(body), result)
+}
+
+func (d *payingDaemon) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return d.JSONRPC(ctx, endpoint, params, response)
+}
+
+func jsonUint(v uint64) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func zeros(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '0'
+	}
+
+	return string(b)
+}
+
+// everyOtherHasher meets any difficulty on even nonces only.
+type everyOtherHasher struct{}
+
+func (everyOtherHasher) Hash(
+	blob []byte, info *daemon.RPCAccessInfoResult,
+) (daemon.Hash, error) {
+	hash := daemon.Hash{}
+	if blob[daemon.PaymentNonceOffset]%2 == 1 {
+		hash[31] = 0xff
+	}
+
+	return hash, nil
+}
+
+func TestPaymentRequester(t *testing.T) {
+	t.Parallel()
+
+	d := &payingDaemon{}
+	signer := daemon.SignerFunc(func() (string, error) {
+		return "signature", nil
+	})
+
+	requester := daemon.NewPaymentRequester(d, signer,
+		daemon.WithHasher(everyOtherHasher{}),
+	)
+
+	_, err := daemon.NewClient(requester).GetMinerData(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []uint32{2}, d.nonces)
+	assert.Equal(t, []uint32{7}, d.cookies)
+	assert.Equal(t, uint64(9), requester.Credits())
+
+	for _, client := range d.clients {
+		assert.Equal(t, "signature", client)
+	}
+}
+
+func TestPaymentRequesterMinCredits(t *testing.T) {
+	t.Parallel()
+
+	d := &payingDaemon{}
+	requester := daemon.NewPaymentRequester(d,
+		daemon.SignerFunc(func() (string, error) {
+			return "signature", nil
+		}),
+		daemon.WithHasher(everyOtherHasher{}),
+		daemon.WithMinCredits(25),
+	)
+
+	_, err := daemon.NewClient(requester).GetMinerData(context.Background())
+	require.NoError(t, err)
+
+	assert.Len(t, d.nonces, 3)
+	assert.Equal(t, uint64(29), requester.Credits())
+}
+
+// neverHasher never meets any difficulty.
+type neverHasher struct{}
+
+func (neverHasher) Hash(
+	blob []byte, info *daemon.RPCAccessInfoResult,
+) (daemon.Hash, error) {
+	hash := daemon.Hash{}
+	hash[31] = 0xff
+
+	return hash, nil
+}
+
+func TestPaymentRequesterMaxMiningJobs(t *testing.T) {
+	t.Parallel()
+
+	d := &payingDaemon{}
+	requester := daemon.NewPaymentRequester(d,
+		daemon.SignerFunc(func() (string, error) {
+			return "signature", nil
+		}),
+		daemon.WithHasher(neverHasher{}),
+		daemon.WithMaxMiningJobs(2),
+	)
+
+	_, err := daemon.NewClient(requester).GetMinerData(context.Background())
+	assert.True(t, errors.Is(err, daemon.ErrMiningExhausted))
+	assert.Empty(t, d.nonces)
+
+	// the request, then a job fetched for each of the two searches plus
+	// the one that gives up.
+	assert.Len(t, d.clients, 4)
+}
+
+func TestCheckHash(t *testing.T) {
+	t.Parallel()
+
+	hash := daemon.Hash{}
+	hash[31] = 0x01 // 2^248
+
+	assert.True(t, daemon.CheckHash(hash, big.NewInt(255)))
+	assert.False(t, daemon.CheckHash(hash, big.NewInt(256)))
+}
@@ -3,25 +3,41 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
 const (
-	endpointGetHeight               = "/get_height"
-	endpointGetLimit                = "/get_limit"
-	endpointGetNetStats             = "/get_net_stats"
-	endpointGetOuts                 = "/get_outs"
-	endpointGetPeerList             = "/get_peer_list"
-	endpointGetPublicNodes          = "/get_public_nodes"
-	endpointGetTransactionPool      = "/get_transaction_pool"
-	endpointGetTransactionPoolStats = "/get_transaction_pool_stats"
-	endpointGetTransactions         = "/get_transactions"
-	endpointMiningStatus            = "/mining_status"
-	endpointSetLimit                = "/set_limit"
-	endpointSetLogLevel             = "/set_log_level"
-	endpointSetLogCategories        = "/set_log_categories"
-	endpointStartMining             = "/start_mining"
-	endpointStopMining              = "/stop_mining"
+	endpointGetAltBlocksHashes          = "/get_alt_blocks_hashes"
+	endpointGetBlocksBin                = "/get_blocks.bin"
+	endpointGetBlocksByHeightBin        = "/get_blocks_by_height.bin"
+	endpointGetHeight                   = "/get_height"
+	endpointGetLimit                    = "/get_limit"
+	endpointGetOutputDistributionBin    = "/get_output_distribution.bin"
+	endpointGetNetStats                 = "/get_net_stats"
+	endpointGetOuts                     = "/get_outs"
+	endpointGetPeerList                 = "/get_peer_list"
+	endpointGetPublicNodes              = "/get_public_nodes"
+	endpointGetTransactionPool          = "/get_transaction_pool"
+	endpointGetTransactionPoolHashes    = "/get_transaction_pool_hashes"
+	endpointGetTransactionPoolHashesBin = "/get_transaction_pool_hashes.bin"
+	endpointGetTransactionPoolStats     = "/get_transaction_pool_stats"
+	endpointGetTransactions             = "/get_transactions"
+	endpointInPeers                     = "/in_peers"
+	endpointIsKeyImageSpent             = "/is_key_image_spent"
+	endpointMiningStatus                = "/mining_status"
+	endpointOutPeers                    = "/out_peers"
+	endpointPopBlocks                   = "/pop_blocks"
+	endpointSaveBC                      = "/save_bc"
+	endpointSendRawTransaction          = "/send_raw_transaction"
+	endpointSetBootstrapDaemon          = "/set_bootstrap_daemon"
+	endpointSetLimit                    = "/set_limit"
+	endpointSetLogLevel                 = "/set_log_level"
+	endpointSetLogCategories            = "/set_log_categories"
+	endpointStartMining                 = "/start_mining"
+	endpointStopDaemon                  = "/stop_daemon"
+	endpointStopMining                  = "/stop_mining"
+	endpointUpdate                      = "/update"
 )
 
 func (c *Client) StopMining(
@@ -37,6 +53,7 @@ func (c *Client) StopMining(
 	return resp, nil
 }
 
+// GetLimit retrieves the bandwidth limits of the node.
 func (c *Client) GetLimit(ctx context.Context) (*GetLimitResult, error) {
 	resp := &GetLimitResult{}
 
@@ -48,6 +65,9 @@ func (c *Client) GetLimit(ctx context.Context) (*GetLimitResult, error) {
 	return resp, nil
 }
 
+// SetLogCategories sets the categories that the daemon should log.
+//
+// (restricted).
 func (c *Client) SetLogCategories(
 	ctx context.Context, params SetLogCategoriesRequestParameters,
 ) (*SetLogCategoriesResult, error) {
@@ -61,6 +81,9 @@ func (c *Client) SetLogCategories(
 	return resp, nil
 }
 
+// SetLogLevel sets the verbosity of the logs of the daemon.
+//
+// (restricted).
 func (c *Client) SetLogLevel(
 	ctx context.Context, params SetLogLevelRequestParameters,
 ) (*SetLogLevelResult, error) {
@@ -74,6 +97,9 @@ func (c *Client) SetLogLevel(
 	return resp, nil
 }
 
+// SetLimit sets the bandwidth limits of the node.
+//
+// (restricted).
 func (c *Client) SetLimit(
 	ctx context.Context, params SetLimitRequestParameters,
 ) (*SetLimitResult, error) {
@@ -219,6 +245,285 @@ func (c *Client) GetNetStats(ctx context.Context) (*GetNetStatsResult, error) {
 	return resp, nil
 }
 
+// GetBlocksBin fetches blocks, along with their transactions and output
+// indices, and optionally information about the transaction pool, in epee's
+// binary format - this is how wallets sync.
+func (c *Client) GetBlocksBin(
+	ctx context.Context, params GetBlocksBinRequestParameters,
+) (*GetBlocksBinResult, error) {
+	resp := &GetBlocksBinResult{}
+
+	err := c.BinaryRequest(ctx, endpointGetBlocksBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetBlocksByHeightBin fetches the blocks, along with their transactions, at
+// each of the heights specified, in epee's binary format.
+func (c *Client) GetBlocksByHeightBin(
+	ctx context.Context, heights []uint64,
+) (*GetBlocksByHeightBinResult, error) {
+	resp := &GetBlocksByHeightBinResult{}
+	params := struct {
+		Heights []uint64 `epee:"heights"`
+	}{heights}
+
+	err := c.BinaryRequest(ctx, endpointGetBlocksByHeightBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputDistributionBin retrieves the distribution of outputs of each of
+// the amounts specified over a range of blocks in epee's binary format,
+// optionally compressed - the distribution is always decoded into
+// `Distribution` regardless.
+func (c *Client) GetOutputDistributionBin(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionResult, error) {
+	resp := &GetOutputDistributionResult{}
+
+	err := c.BinaryRequest(ctx, endpointGetOutputDistributionBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	if err := resp.decompress(); err != nil {
+		return nil, fmt.Errorf("decompress: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SendRawTransaction broadcasts a transaction blob to the network (unless
+// `DoNotRelay` is set).
+//
+// When the daemon rejects the transaction, the error is a `*TxRejectedError`
+// from which the rejection flags can be inspected.
+func (c *Client) SendRawTransaction(
+	ctx context.Context, params SendRawTransactionRequestParameters,
+) (*SendRawTransactionResult, error) {
+	resp := &SendRawTransactionResult{}
+
+	err := c.RawRequest(ctx, endpointSendRawTransaction, params, resp)
+	if err != nil {
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.Status == StatusFailed {
+			err = &TxRejectedError{Result: resp, err: err}
+		}
+
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// IsKeyImageSpent checks whether each of the key images (hex-encoded) has
+// been spent, either in the chain or in the pool.
+func (c *Client) IsKeyImageSpent(
+	ctx context.Context, keyImages []string,
+) (*IsKeyImageSpentResult, error) {
+	resp := &IsKeyImageSpentResult{}
+	params := map[string]interface{}{
+		"key_images": keyImages,
+	}
+
+	err := c.RawRequest(ctx, endpointIsKeyImageSpent, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTransactionPoolHashes retrieves the hashes of the transactions in the
+// pool.
+func (c *Client) GetTransactionPoolHashes(
+	ctx context.Context,
+) (*GetTransactionPoolHashesResult, error) {
+	resp := &GetTransactionPoolHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetTransactionPoolHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTransactionPoolHashesBin retrieves the hashes of the transactions in the
+// pool in epee's binary format.
+func (c *Client) GetTransactionPoolHashesBin(
+	ctx context.Context,
+) (*GetTransactionPoolHashesBinResult, error) {
+	resp := &GetTransactionPoolHashesBinResult{}
+
+	err := c.BinaryRequest(ctx, endpointGetTransactionPoolHashesBin, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTransactions decodes the `tx_json` of each of the transactions in the
+// pool.
+func (r *GetTransactionPoolResult) GetTransactions() ([]*TransactionJSON, error) {
+	txns := make([]*TransactionJSON, len(r.Transactions))
+
+	for idx, txn := range r.Transactions {
+		if len(txn.TxJSON) == 0 {
+			return nil, fmt.Errorf("txn w/ empty `.tx_json`: %s",
+				txn.IDHash)
+		}
+
+		t := &TransactionJSON{}
+		err := json.Unmarshal([]byte(txn.TxJSON), t)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal txn '%s': %w",
+				txn.IDHash, err)
+		}
+
+		txns[idx] = t
+	}
+
+	return txns, nil
+}
+
+// SaveBC saves the blockchain to disk.
+//
+// (restricted).
+func (c *Client) SaveBC(ctx context.Context) (*SaveBCResult, error) {
+	resp := &SaveBCResult{}
+
+	err := c.RawRequest(ctx, endpointSaveBC, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PopBlocks removes the `nblocks` most recent blocks from the chain.
+//
+// (restricted).
+func (c *Client) PopBlocks(
+	ctx context.Context, nblocks uint64,
+) (*PopBlocksResult, error) {
+	resp := &PopBlocksResult{}
+	params := map[string]interface{}{
+		"nblocks": nblocks,
+	}
+
+	err := c.RawRequest(ctx, endpointPopBlocks, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// OutPeers limits the number of outgoing peers (or retrieves the current
+// limit, if `Set` is false).
+//
+// (restricted).
+func (c *Client) OutPeers(
+	ctx context.Context, params OutPeersRequestParameters,
+) (*OutPeersResult, error) {
+	resp := &OutPeersResult{}
+
+	err := c.RawRequest(ctx, endpointOutPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// InPeers limits the number of incoming peers (or retrieves the current
+// limit, if `Set` is false).
+//
+// (restricted).
+func (c *Client) InPeers(
+	ctx context.Context, params InPeersRequestParameters,
+) (*InPeersResult, error) {
+	resp := &InPeersResult{}
+
+	err := c.RawRequest(ctx, endpointInPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// StopDaemon sends a command to the daemon to safely shut down.
+//
+// (restricted).
+func (c *Client) StopDaemon(ctx context.Context) (*StopDaemonResult, error) {
+	resp := &StopDaemonResult{}
+
+	err := c.RawRequest(ctx, endpointStopDaemon, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SetBootstrapDaemon sets (or unsets, with an empty address) the daemon that
+// requests are forwarded to while the node is still syncing.
+//
+// (restricted).
+func (c *Client) SetBootstrapDaemon(
+	ctx context.Context, params SetBootstrapDaemonRequestParameters,
+) (*SetBootstrapDaemonResult, error) {
+	resp := &SetBootstrapDaemonResult{}
+
+	err := c.RawRequest(ctx, endpointSetBootstrapDaemon, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Update checks for an update to the daemon, optionally downloading it.
+//
+// (restricted).
+func (c *Client) Update(
+	ctx context.Context, params UpdateRequestParameters,
+) (*UpdateResult, error) {
+	resp := &UpdateResult{}
+
+	err := c.RawRequest(ctx, endpointUpdate, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetAltBlocksHashes retrieves the hashes of all the blocks in alternative
+// chains seen by the node.
+func (c *Client) GetAltBlocksHashes(
+	ctx context.Context,
+) (*GetAltBlocksHashesResult, error) {
+	resp := &GetAltBlocksHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetAltBlocksHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
 func (r *GetTransactionsResult) GetTransactions() ([]*TransactionJSON, error) {
 	txns := make([]*TransactionJSON, len(r.Txs))
 
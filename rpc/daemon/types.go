@@ -1,23 +1,56 @@
 package daemon
 
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
 // RPCResultFooter contains the set of fields that every RPC result message
 // will contain.
 type RPCResultFooter struct {
 	// Status dictates whether the request worked or not. "OK" means good.
-	Status string `json:"status"`
+	Status string `json:"status" epee:"status"`
 
 	// States if the result is obtained using the bootstrap mode, and is
 	// therefore not trusted (`true`), or when the daemon is fully synced
 	// and thus handles the RPC locally (`false`).
-	Untrusted bool `json:"untrusted"`
+	Untrusted bool `json:"untrusted" epee:"untrusted"`
 
 	// Credits indicates the number of credits available to the requesting
 	// client, if payment for RPC is enabled, otherwise, 0.
-	Credits uint64 `json:"credits,omitempty"`
+	Credits uint64 `json:"credits,omitempty" epee:"credits,omitempty"`
 
 	// TopHash is the hash of the highest block in the chain, If payment
 	// for RPC is enabled, otherwise, empty.
-	TopHash string `json:"top_hash,omitempty"`
+	TopHash string `json:"top_hash,omitempty" epee:"top_hash,omitempty"`
+}
+
+// Footer gives access to the footer of the result embedding it.
+func (f *RPCResultFooter) Footer() *RPCResultFooter {
+	return f
+}
+
+// RPCStatus gives the status and the untrusted flag of the result, letting
+// code unaware of the type of the result (e.g., tracing) inspect them.
+func (f *RPCResultFooter) RPCStatus() (string, bool) {
+	return f.Status, f.Untrusted
+}
+
+// Err returns a `*StatusError` if the daemon reported a status other than
+// `StatusOK`, or nil otherwise.
+//
+// ps.: an empty status is not considered a failure as not every result sets
+// it.
+func (f *RPCResultFooter) Err() error {
+	if f.Status == "" || f.Status == StatusOK {
+		return nil
+	}
+
+	return &StatusError{Status: f.Status}
 }
 
 // GetAlternateChainsResult is the result of a call to the GetAlternateChains
@@ -557,15 +590,10 @@ func (c *GetBlockResultJSON) MinerOutputs() uint64 {
 
 // SyncInfoResult is the result of a call to the SyncInfo RPC method.
 type SyncInfoResult struct {
-	Credits uint64 `json:"credits"`
-
 	Height                uint64 `json:"height"`
 	NextNeededPruningSeed uint64 `json:"next_needed_pruning_seed"`
 	Overview              string `json:"overview"`
-	Status                string `json:"status"`
 	TargetHeight          uint64 `json:"target_height"`
-	TopHash               string `json:"top_hash"`
-	Untrusted             bool   `json:"untrusted"`
 	Peers                 []struct {
 		Info struct {
 			Address           string `json:"address"`
@@ -698,12 +726,10 @@ type GetTransactionsResultTransaction struct {
 }
 
 type GetTransactionsResult struct {
-	Credits   int                                `json:"credits"`
-	Status    string                             `json:"status"`
-	TopHash   string                             `json:"top_hash"`
-	Txs       []GetTransactionsResultTransaction `json:"txs"`
-	TxsAsHex  []string                           `json:"txs_as_hex"`
-	Untrusted bool                               `json:"untrusted"`
+	Txs      []GetTransactionsResultTransaction `json:"txs"`
+	TxsAsHex []string                           `json:"txs_as_hex"`
+
+	RPCResultFooter `json:",inline"`
 }
 
 type TransactionJSON struct {
@@ -773,34 +799,170 @@ type TransactionJSON struct {
 }
 
 type GetTransactionPoolResult struct {
-	Credits        int `json:"credits"`
 	SpentKeyImages []struct {
 		IDHash    string   `json:"id_hash"`
 		TxsHashes []string `json:"txs_hashes"`
 	} `json:"spent_key_images"`
-	Status       string `json:"status"`
-	TopHash      string `json:"top_hash"`
-	Transactions []struct {
-		BlobSize           uint64 `json:"blob_size"`
-		DoNotRelay         bool   `json:"do_not_relay"`
-		DoubleSpendSeen    bool   `json:"double_spend_seen"`
-		Fee                uint64 `json:"fee"`
-		IDHash             string `json:"id_hash"`
-		KeptByBlock        bool   `json:"kept_by_block"`
-		LastFailedHeight   uint64 `json:"last_failed_height"`
-		LastFailedIDHash   string `json:"last_failed_id_hash"`
-		LastRelayedTime    uint64 `json:"last_relayed_time"`
-		MaxUsedBlockHeight uint64 `json:"max_used_block_height"`
-		MaxUsedBlockIDHash string `json:"max_used_block_id_hash"`
-		ReceiveTime        int64  `json:"receive_time"`
-		Relayed            bool   `json:"relayed"`
-		TxBlob             string `json:"tx_blob"`
-		TxJSON             string `json:"tx_json"`
-		Weight             uint64 `json:"weight"`
-	} `json:"transactions"`
-	Untrusted bool `json:"untrusted"`
+	Transactions []GetTransactionPoolResultTransaction `json:"transactions"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetTransactionPoolResultTransaction is a transaction in the pool as sent
+// back by GetTransactionPool.
+type GetTransactionPoolResultTransaction struct {
+	BlobSize           uint64 `json:"blob_size"`
+	DoNotRelay         bool   `json:"do_not_relay"`
+	DoubleSpendSeen    bool   `json:"double_spend_seen"`
+	Fee                uint64 `json:"fee"`
+	IDHash             string `json:"id_hash"`
+	KeptByBlock        bool   `json:"kept_by_block"`
+	LastFailedHeight   uint64 `json:"last_failed_height"`
+	LastFailedIDHash   string `json:"last_failed_id_hash"`
+	LastRelayedTime    uint64 `json:"last_relayed_time"`
+	MaxUsedBlockHeight uint64 `json:"max_used_block_height"`
+	MaxUsedBlockIDHash string `json:"max_used_block_id_hash"`
+	ReceiveTime        int64  `json:"receive_time"`
+	Relayed            bool   `json:"relayed"`
+	TxBlob             string `json:"tx_blob"`
+	TxJSON             string `json:"tx_json"`
+	Weight             uint64 `json:"weight"`
+}
+
+// GetTransactionPoolHashesResult is the result of a call to the
+// GetTransactionPoolHashes method.
+type GetTransactionPoolHashesResult struct {
+	TxHashes []string `json:"tx_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetTransactionPoolHashesBinResult is the result of a call to the
+// GetTransactionPoolHashesBin method.
+type GetTransactionPoolHashesBinResult struct {
+	TxHashes []Hash `epee:"tx_hashes,blob"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// TxBacklogEntry summarizes a transaction in the pool.
+type TxBacklogEntry struct {
+	Weight     uint64
+	Fee        uint64
+	TimeInPool uint64
+}
+
+// txBacklogEntrySize is the size of a TxBacklogEntry when packed into a
+// blob.
+const txBacklogEntrySize = 24
+
+// TxBacklog is the list of transactions in the pool as sent back by
+// GetTxPoolBacklog.
+//
+// `monerod` sends it as a blob of packed entries even over JSONRPC, i.e., a
+// JSON string whose bytes (once unescaped) are the raw blob - this type
+// decodes it from that form.
+type TxBacklog []TxBacklogEntry
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *TxBacklog) UnmarshalJSON(data []byte) error {
+	blob, err := unquoteBlob(data)
+	if err != nil {
+		return fmt.Errorf("unquote: %w", err)
+	}
+
+	if len(blob)%txBacklogEntrySize != 0 {
+		return fmt.Errorf("blob size %d not a multiple of %d",
+			len(blob), txBacklogEntrySize)
+	}
+
+	backlog := make(TxBacklog, len(blob)/txBacklogEntrySize)
+	for i := range backlog {
+		entry := blob[i*txBacklogEntrySize:]
+
+		backlog[i] = TxBacklogEntry{
+			Weight:     binary.LittleEndian.Uint64(entry[0:]),
+			Fee:        binary.LittleEndian.Uint64(entry[8:]),
+			TimeInPool: binary.LittleEndian.Uint64(entry[16:]),
+		}
+	}
+
+	*b = backlog
+
+	return nil
 }
 
+// unquoteBlob unquotes a JSON string carrying binary data, byte by byte.
+//
+// `encoding/json` can't be used for this as it replaces bytes that aren't
+// valid UTF-8.
+func unquoteBlob(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return nil, fmt.Errorf("not a json string")
+	}
+
+	data = data[1 : len(data)-1]
+	blob := make([]byte, 0, len(data))
+
+	for i := 0; i < len(data); i++ {
+		if data[i] != '\\' {
+			blob = append(blob, data[i])
+			continue
+		}
+
+		i++
+		if i == len(data) {
+			return nil, fmt.Errorf("unterminated escape sequence")
+		}
+
+		switch data[i] {
+		case '"', '\\', '/':
+			blob = append(blob, data[i])
+		case 'b':
+			blob = append(blob, '\b')
+		case 'f':
+			blob = append(blob, '\f')
+		case 'n':
+			blob = append(blob, '\n')
+		case 'r':
+			blob = append(blob, '\r')
+		case 't':
+			blob = append(blob, '\t')
+		case 'u':
+			if i+4 >= len(data) {
+				return nil, fmt.Errorf("truncated unicode escape")
+			}
+
+			v, err := strconv.ParseUint(string(data[i+1:i+5]), 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("unicode escape: %w", err)
+			}
+
+			blob = append(blob, byte(v))
+			i += 4
+		default:
+			return nil, fmt.Errorf("invalid escape character %q", data[i])
+		}
+	}
+
+	return blob, nil
+}
+
+// GetTxPoolBacklogResult is the result of a call to the GetTxPoolBacklog
+// method.
+type GetTxPoolBacklogResult struct {
+	Backlog TxBacklog `json:"backlog"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// FlushTxPoolResult is the result of a call to the FlushTxPool method.
+type FlushTxPoolResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// SetLogCategoriesRequestParameters is the set of parameters to be passed
+// to the SetLogCategories method.
 type SetLogCategoriesRequestParameters struct {
 	// Categories to log with their corresponding levels formatted as a
 	// comma-separated list of <category>:<level> pairs.
@@ -810,21 +972,28 @@ type SetLogCategoriesRequestParameters struct {
 	Categories string `json:"categories"`
 }
 
+// SetLogCategoriesResult is the result of a call to the SetLogCategories
+// method.
 type SetLogCategoriesResult struct {
 	Categories      string `json:"categories"`
 	RPCResultFooter `json:",inline"`
 }
 
+// SetLogLevelRequestParameters is the set of parameters to be passed to the
+// SetLogLevel method.
 type SetLogLevelRequestParameters struct {
 	// Level is the log level that the daemon should use. From 0 to 4 (less
 	// verbose to more verbose).
 	Level int8 `json:"level"`
 }
 
+// SetLogLevelResult is the result of a call to the SetLogLevel method.
 type SetLogLevelResult struct {
 	RPCResultFooter `json:",inline"`
 }
 
+// SetLimitRequestParameters is the set of parameters to be passed to the
+// SetLimit method.
 type SetLimitRequestParameters struct {
 	// LimitUp is the upload limit in kB/s
 	LimitUp uint64 `json:"limit_up"`
@@ -832,6 +1001,7 @@ type SetLimitRequestParameters struct {
 	LimitDown uint64 `json:"limit_down"`
 }
 
+// SetLimitResult is the result of a call to the SetLimit method.
 type SetLimitResult struct {
 	// LimitUp is the upload limit in kB/s
 	LimitUp uint64 `json:"limit_up"`
@@ -841,6 +1011,7 @@ type SetLimitResult struct {
 	RPCResultFooter `json:",inline"`
 }
 
+// GetLimitResult is the result of a call to the GetLimit method.
 type GetLimitResult struct {
 	// LimitUp is the upload limit in kB/s
 	LimitUp uint64 `json:"limit_up"`
@@ -873,8 +1044,9 @@ type CalcPowParameters struct {
 }
 
 type SubmitBlockResult struct {
-	Status  string `json:"status"`
 	BlockId string `json:"block_id"` // Submitted block's hash as a hexadecimal string
+
+	RPCResultFooter `json:",inline"`
 }
 
 type GetMinerDataResult struct {
@@ -885,5 +1057,771 @@ type GetMinerDataResult struct {
 	Difficulty            string `json:"difficulty"`
 	MedianWeight          uint64 `json:"median_weight"`
 	AlreadyGeneratedCoins uint64 `json:"already_generated_coins"`
-	Untrusted             bool   `json:"untrusted"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// Hash is a 32-byte hash (of a block, transaction, etc) as transferred by the
+// binary endpoints. Its text form (e.g., when encoding to JSON) is the same
+// hexadecimal representation used by the JSON endpoints.
+type Hash [32]byte
+
+// ParseHash parses the hexadecimal representation of a hash.
+func ParseHash(s string) (Hash, error) {
+	h := Hash{}
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return h, fmt.Errorf("decode hex: %w", err)
+	}
+
+	if len(b) != len(h) {
+		return h, fmt.Errorf("expected %d bytes, got %d", len(h), len(b))
+	}
+
+	copy(h[:], b)
+
+	return h, nil
+}
+
+// String returns the hexadecimal representation of the hash.
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (h Hash) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (h *Hash) UnmarshalText(text []byte) error {
+	parsed, err := ParseHash(string(text))
+	if err != nil {
+		return err
+	}
+
+	*h = parsed
+
+	return nil
+}
+
+// GetBlocksRequestedInfo indicates what a call to GetBlocksBin should bring
+// back.
+type GetBlocksRequestedInfo uint8
+
+const (
+	// GetBlocksBlocksOnly requests only blocks.
+	GetBlocksBlocksOnly GetBlocksRequestedInfo = 0
+
+	// GetBlocksBlocksAndPool requests blocks as well as information
+	// about the transaction pool.
+	GetBlocksBlocksAndPool GetBlocksRequestedInfo = 1
+
+	// GetBlocksPoolOnly requests only information about the transaction
+	// pool.
+	GetBlocksPoolOnly GetBlocksRequestedInfo = 2
+)
+
+// GetBlocksBinRequestParameters is the set of parameters to be passed to the
+// GetBlocksBin method.
+type GetBlocksBinRequestParameters struct {
+	// RequestedInfo dictates whether blocks, pool information, or both
+	// should be sent back.
+	RequestedInfo GetBlocksRequestedInfo `epee:"requested_info,omitempty"`
+
+	// BlockIDs is the list of hashes of blocks known to the caller: the
+	// first 10 most recent, then in powers of 2 distance, ending with the
+	// genesis block. The daemon sends blocks starting from the first
+	// block in common with its main chain.
+	BlockIDs []Hash `epee:"block_ids,blob"`
+
+	// StartHeight is the height of the first block to be sent back, if
+	// higher than the one found via BlockIDs.
+	StartHeight uint64 `epee:"start_height"`
+
+	// Prune indicates that transactions should be sent back in their
+	// pruned form.
+	Prune bool `epee:"prune"`
+
+	// NoMinerTx indicates that miner transactions should be left out of
+	// the output indices.
+	NoMinerTx bool `epee:"no_miner_tx,omitempty"`
+
+	// PoolInfoSince is the UNIX timestamp since which pool information
+	// is desired (0 for everything).
+	PoolInfoSince uint64 `epee:"pool_info_since,omitempty"`
+}
+
+// TxBlobEntry is a transaction that is part of a block sent back by the
+// binary endpoints.
+type TxBlobEntry struct {
+	// Blob is the transaction blob, pruned if requested.
+	Blob []byte `epee:"blob"`
+
+	// PrunableHash is the hash of the prunable part of the transaction,
+	// only set when the blob is pruned.
+	PrunableHash Hash `epee:"prunable_hash"`
+}
+
+// UnmarshalEpee decodes the entry from either its object form, used when
+// transactions are pruned, or a bare blob, used otherwise.
+func (e *TxBlobEntry) UnmarshalEpee(v interface{}) error {
+	switch value := v.(type) {
+	case string:
+		e.Blob = []byte(value)
+	case map[string]interface{}:
+		blob, _ := value["blob"].(string)
+		e.Blob = []byte(blob)
+
+		if hash, ok := value["prunable_hash"].(string); ok {
+			if len(hash) != len(e.PrunableHash) {
+				return fmt.Errorf("prunable hash: expected %d "+
+					"bytes, got %d", len(e.PrunableHash), len(hash))
+			}
+
+			copy(e.PrunableHash[:], hash)
+		}
+	default:
+		return fmt.Errorf("unexpected tx blob entry of type %T", v)
+	}
+
+	return nil
+}
+
+// BlockCompleteEntry is a block, along with its transactions, sent back by
+// the binary endpoints.
+type BlockCompleteEntry struct {
+	// Pruned indicates whether the transactions are pruned.
+	Pruned bool `epee:"pruned"`
+
+	// Block is the block blob.
+	Block []byte `epee:"block"`
+
+	// BlockWeight is the weight of the block, only set when pruned.
+	BlockWeight uint64 `epee:"block_weight"`
+
+	// Txs contains the blobs of the (non-coinbase) transactions of the
+	// block.
+	Txs []TxBlobEntry `epee:"txs"`
+}
+
+// TxOutputIndices holds the global output indices of the outputs of a
+// transaction.
+type TxOutputIndices struct {
+	Indices []uint64 `epee:"indices"`
+}
+
+// BlockOutputIndices holds the global output indices of every transaction
+// in a block, starting with the coinbase transaction (unless not requested).
+type BlockOutputIndices struct {
+	Indices []TxOutputIndices `epee:"indices"`
+}
+
+// PoolTxInfo is a transaction in the pool, as sent back by GetBlocksBin.
+type PoolTxInfo struct {
+	TxHash          Hash   `epee:"tx_hash"`
+	TxBlob          []byte `epee:"tx_blob"`
+	DoubleSpendSeen bool   `epee:"double_spend_seen"`
+}
+
+// GetBlocksBinResult is the result of a call to the GetBlocksBin method.
+type GetBlocksBinResult struct {
+	// Blocks is the list of blocks, along with their transactions.
+	Blocks []BlockCompleteEntry `epee:"blocks"`
+
+	// StartHeight is the height of the first block in Blocks.
+	StartHeight uint64 `epee:"start_height"`
+
+	// CurrentHeight is the height of the chain of the daemon.
+	CurrentHeight uint64 `epee:"current_height"`
+
+	// OutputIndices contains, for each block, the global output indices
+	// of the outputs of each of its transactions.
+	OutputIndices []BlockOutputIndices `epee:"output_indices"`
+
+	// DaemonTime is the UNIX timestamp at which the daemon replied, to be
+	// used as `PoolInfoSince` in a subsequent request.
+	DaemonTime uint64 `epee:"daemon_time"`
+
+	// PoolInfoExtent indicates which pool information has been sent
+	// back: 0 for none, 1 for incremental, 2 for full.
+	PoolInfoExtent uint8 `epee:"pool_info_extent"`
+
+	// AddedPoolTxs are the transactions added to the pool since
+	// `PoolInfoSince`.
+	AddedPoolTxs []PoolTxInfo `epee:"added_pool_txs"`
+
+	// RemainingAddedPoolTxIDs are the IDs of the transactions added to
+	// the pool that weren't sent in AddedPoolTxs.
+	RemainingAddedPoolTxIDs []Hash `epee:"remaining_added_pool_txids,blob"`
+
+	// RemovedPoolTxIDs are the IDs of the transactions removed from the
+	// pool since `PoolInfoSince` (only for incremental information).
+	RemovedPoolTxIDs []Hash `epee:"removed_pool_txids,blob"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetBlocksByHeightBinResult is the result of a call to the
+// GetBlocksByHeightBin method.
+type GetBlocksByHeightBinResult struct {
+	Blocks []BlockCompleteEntry `epee:"blocks"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetOutputDistributionRequestParameters is the set of parameters to be
+// passed to the GetOutputDistribution and GetOutputDistributionBin methods.
+type GetOutputDistributionRequestParameters struct {
+	// Amounts is the list of amounts to look up the distribution of (0
+	// for RingCT outputs).
+	Amounts []uint64 `json:"amounts" epee:"amounts"`
+
+	// FromHeight is the starting height (inclusive).
+	FromHeight uint64 `json:"from_height,omitempty" epee:"from_height,omitempty"`
+
+	// ToHeight is the ending height (inclusive), 0 meaning the current
+	// height.
+	ToHeight uint64 `json:"to_height,omitempty" epee:"to_height,omitempty"`
+
+	// Cumulative indicates that the distribution should be cumulative
+	// rather than the number of outputs per block.
+	Cumulative bool `json:"cumulative,omitempty" epee:"cumulative,omitempty"`
+
+	// Binary indicates that the distribution should be sent as a blob
+	// rather than a list of integers.
+	//
+	// ps.: this only makes sense for GetOutputDistributionBin - over
+	// JSONRPC blobs can't be represented reliably, thus
+	// GetOutputDistribution always sets it to false.
+	//
+	Binary bool `json:"binary" epee:"binary"`
+
+	// Compress indicates that the distribution should be sent as a
+	// sequence of varints (requires Binary).
+	Compress bool `json:"compress,omitempty" epee:"compress,omitempty"`
+}
+
+// OutputDistribution is the distribution of outputs of a given amount over
+// a range of blocks.
+type OutputDistribution struct {
+	// Amount is the amount that the distribution refers to.
+	Amount uint64 `json:"amount" epee:"amount"`
+
+	// StartHeight is the height of the first block in the distribution.
+	StartHeight uint64 `json:"start_height" epee:"start_height"`
+
+	// Base is the number of outputs before StartHeight (for cumulative
+	// distributions).
+	Base uint64 `json:"base" epee:"base"`
+
+	// Binary indicates whether the distribution was sent as a blob.
+	Binary bool `json:"binary" epee:"binary"`
+
+	// Compress indicates whether the distribution was sent compressed.
+	Compress bool `json:"compress" epee:"compress"`
+
+	// Distribution is the number of outputs (or cumulative number of
+	// outputs) for each block starting at StartHeight.
+	//
+	// Compressed distributions are decoded into it, so this is always
+	// filled regardless of how the daemon sent it.
+	//
+	Distribution []uint64 `json:"distribution" epee:"distribution"`
+
+	// CompressedData is the distribution as sent by the daemon when
+	// compressed.
+	CompressedData string `json:"compressed_data,omitempty" epee:"compressed_data"`
+}
+
+// decompress fills Distribution from CompressedData when the distribution
+// was sent compressed, i.e., as a sequence of LEB128 varints.
+func (d *OutputDistribution) decompress() error {
+	if !d.Compress || d.CompressedData == "" {
+		return nil
+	}
+
+	data := []byte(d.CompressedData)
+	distribution := make([]uint64, 0, len(data))
+
+	for len(data) > 0 {
+		v, n := binary.Uvarint(data)
+		if n <= 0 {
+			return fmt.Errorf("malformed varint at offset %d",
+				len(d.CompressedData)-len(data))
+		}
+
+		distribution = append(distribution, v)
+		data = data[n:]
+	}
+
+	d.Distribution = distribution
+
+	return nil
+}
+
+// GetOutputDistributionResult is the result of a call to the
+// GetOutputDistribution and GetOutputDistributionBin methods.
+type GetOutputDistributionResult struct {
+	Distributions []OutputDistribution `json:"distributions" epee:"distributions"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// decompress decodes all compressed distributions.
+func (r *GetOutputDistributionResult) decompress() error {
+	for i := range r.Distributions {
+		if err := r.Distributions[i].decompress(); err != nil {
+			return fmt.Errorf("distribution for amount %d: %w",
+				r.Distributions[i].Amount, err)
+		}
+	}
+
+	return nil
+}
+
+// GetOutputHistogramRequestParameters is the set of parameters to be passed
+// to the GetOutputHistogram method.
+type GetOutputHistogramRequestParameters struct {
+	// Amounts is the list of amounts to look up.
+	Amounts []uint64 `json:"amounts"`
+
+	// MinCount is the minimum number of instances for an amount to be
+	// included.
+	MinCount uint64 `json:"min_count,omitempty"`
+
+	// MaxCount is the maximum number of instances for an amount to be
+	// included.
+	MaxCount uint64 `json:"max_count,omitempty"`
+
+	// Unlocked indicates that only unlocked outputs should be counted.
+	Unlocked bool `json:"unlocked,omitempty"`
+
+	// RecentCutoff is the UNIX timestamp after which outputs are
+	// considered recent.
+	RecentCutoff uint64 `json:"recent_cutoff,omitempty"`
+}
+
+// GetOutputHistogramResult is the result of a call to the
+// GetOutputHistogram method.
+type GetOutputHistogramResult struct {
+	Histogram []struct {
+		Amount            uint64 `json:"amount"`
+		TotalInstances    uint64 `json:"total_instances"`
+		UnlockedInstances uint64 `json:"unlocked_instances"`
+		RecentInstances   uint64 `json:"recent_instances"`
+	} `json:"histogram"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SendRawTransactionRequestParameters is the set of parameters to be passed
+// to the SendRawTransaction method.
+type SendRawTransactionRequestParameters struct {
+	// TxAsHex is the full transaction blob, hex-encoded.
+	TxAsHex string `json:"tx_as_hex"`
+
+	// DoNotRelay indicates that the transaction should be added to the
+	// pool but not relayed to the network.
+	DoNotRelay bool `json:"do_not_relay,omitempty"`
+
+	// DoSanityChecks indicates whether the daemon should perform sanity
+	// checks (e.g., on the ring members) before accepting the
+	// transaction. Left unset, the daemon defaults to `true`.
+	DoSanityChecks *bool `json:"do_sanity_checks,omitempty"`
+}
+
+// SendRawTransactionResult is the result of a call to the SendRawTransaction
+// method.
+type SendRawTransactionResult struct {
+	// Reason is a human-readable description of why the transaction was
+	// rejected (if it was).
+	Reason string `json:"reason"`
+
+	// NotRelayed indicates that the transaction was not relayed.
+	NotRelayed bool `json:"not_relayed"`
+
+	// LowMixin indicates that the ring size is too low.
+	LowMixin bool `json:"low_mixin"`
+
+	// DoubleSpend indicates that the transaction spends a key image that
+	// has already been spent.
+	DoubleSpend bool `json:"double_spend"`
+
+	// InvalidInput indicates that the inputs are invalid.
+	InvalidInput bool `json:"invalid_input"`
+
+	// InvalidOutput indicates that the outputs are invalid.
+	InvalidOutput bool `json:"invalid_output"`
+
+	// TooBig indicates that the transaction is too big.
+	TooBig bool `json:"too_big"`
+
+	// Overspend indicates that the transaction spends more than its
+	// inputs add up to.
+	Overspend bool `json:"overspend"`
+
+	// FeeTooLow indicates that the fee is too low.
+	FeeTooLow bool `json:"fee_too_low"`
+
+	// TooFewOutputs indicates that the transaction has fewer than two
+	// outputs.
+	TooFewOutputs bool `json:"too_few_outputs"`
+
+	// SanityCheckFailed indicates that the transaction failed the sanity
+	// checks (see `DoSanityChecks`).
+	SanityCheckFailed bool `json:"sanity_check_failed"`
+
+	// TxExtraTooBig indicates that the tx_extra field is too big.
+	TxExtraTooBig bool `json:"tx_extra_too_big"`
+
+	// NonzeroUnlockTime indicates that the transaction sets an unlock
+	// time, which is no longer allowed.
+	NonzeroUnlockTime bool `json:"nonzero_unlock_time"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// Reasons lists the names of all the rejection flags set in the result,
+// using the same names as the JSON fields (e.g., "double_spend").
+func (r *SendRawTransactionResult) Reasons() []string {
+	reasons := []string{}
+
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{r.LowMixin, "low_mixin"},
+		{r.DoubleSpend, "double_spend"},
+		{r.InvalidInput, "invalid_input"},
+		{r.InvalidOutput, "invalid_output"},
+		{r.TooBig, "too_big"},
+		{r.Overspend, "overspend"},
+		{r.FeeTooLow, "fee_too_low"},
+		{r.TooFewOutputs, "too_few_outputs"},
+		{r.SanityCheckFailed, "sanity_check_failed"},
+		{r.TxExtraTooBig, "tx_extra_too_big"},
+		{r.NonzeroUnlockTime, "nonzero_unlock_time"},
+	} {
+		if flag.set {
+			reasons = append(reasons, flag.name)
+		}
+	}
+
+	return reasons
+}
+
+// KeyImageSpentStatus indicates whether a key image has been spent, and if
+// so, where.
+type KeyImageSpentStatus int
+
+const (
+	// KeyImageUnspent indicates that the key image hasn't been spent.
+	KeyImageUnspent KeyImageSpentStatus = 0
+
+	// KeyImageSpentInBlockchain indicates that the key image has been
+	// spent by a transaction in the chain.
+	KeyImageSpentInBlockchain KeyImageSpentStatus = 1
+
+	// KeyImageSpentInPool indicates that the key image has been spent by
+	// a transaction in the pool.
+	KeyImageSpentInPool KeyImageSpentStatus = 2
+)
+
+// String returns a human-readable representation of the status.
+func (s KeyImageSpentStatus) String() string {
+	switch s {
+	case KeyImageUnspent:
+		return "unspent"
+	case KeyImageSpentInBlockchain:
+		return "spent in blockchain"
+	case KeyImageSpentInPool:
+		return "spent in pool"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// IsKeyImageSpentResult is the result of a call to the IsKeyImageSpent
+// method.
+type IsKeyImageSpentResult struct {
+	// SpentStatus is the status of each key image, in the same order as
+	// requested.
+	SpentStatus []KeyImageSpentStatus `json:"spent_status"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SaveBCResult is the result of a call to the SaveBC method.
+type SaveBCResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// PopBlocksResult is the result of a call to the PopBlocks method.
+type PopBlocksResult struct {
+	// Height is the height of the chain after popping the blocks.
+	Height uint64 `json:"height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// PruneBlockchainResult is the result of a call to the PruneBlockchain
+// method.
+type PruneBlockchainResult struct {
+	// Pruned indicates whether the chain is pruned.
+	Pruned bool `json:"pruned"`
+
+	// PruningSeed is the seed used for pruning (0 when not pruned).
+	PruningSeed uint32 `json:"pruning_seed"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// OutPeersRequestParameters is the set of parameters to be passed to the
+// OutPeers method.
+type OutPeersRequestParameters struct {
+	// Set indicates whether the limit should be set (otherwise, the
+	// current one is only retrieved). Left unset, the daemon defaults
+	// to `true`.
+	Set *bool `json:"set,omitempty"`
+
+	// OutPeers is the maximum number of outgoing peers.
+	OutPeers uint32 `json:"out_peers"`
+}
+
+// OutPeersResult is the result of a call to the OutPeers method.
+type OutPeersResult struct {
+	// OutPeers is the maximum number of outgoing peers.
+	OutPeers uint32 `json:"out_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// InPeersRequestParameters is the set of parameters to be passed to the
+// InPeers method.
+type InPeersRequestParameters struct {
+	// Set indicates whether the limit should be set (otherwise, the
+	// current one is only retrieved). Left unset, the daemon defaults
+	// to `true`.
+	Set *bool `json:"set,omitempty"`
+
+	// InPeers is the maximum number of incoming peers.
+	InPeers uint32 `json:"in_peers"`
+}
+
+// InPeersResult is the result of a call to the InPeers method.
+type InPeersResult struct {
+	// InPeers is the maximum number of incoming peers.
+	InPeers uint32 `json:"in_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// StopDaemonResult is the result of a call to the StopDaemon method.
+type StopDaemonResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// SetBootstrapDaemonRequestParameters is the set of parameters to be passed
+// to the SetBootstrapDaemon method.
+type SetBootstrapDaemonRequestParameters struct {
+	// Address is the address of the bootstrap daemon (<host>:<port>),
+	// "auto" for picking one automatically, or empty for disabling it.
+	Address string `json:"address"`
+
+	// Username is the username for logging into the bootstrap daemon.
+	Username string `json:"username,omitempty"`
+
+	// Password is the password for logging into the bootstrap daemon.
+	Password string `json:"password,omitempty"`
+
+	// Proxy is the address of a SOCKS proxy (<ip>:<port>) to reach the
+	// bootstrap daemon through.
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// SetBootstrapDaemonResult is the result of a call to the SetBootstrapDaemon
+// method.
+type SetBootstrapDaemonResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// FlushCacheResult is the result of a call to the FlushCache method.
+type FlushCacheResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// UpdateCommand is the action that a call to the Update method should take.
+type UpdateCommand string
+
+const (
+	// UpdateCheck only checks whether an update is available.
+	UpdateCheck UpdateCommand = "check"
+
+	// UpdateDownload checks whether an update is available, downloading
+	// it if so.
+	UpdateDownload UpdateCommand = "download"
+)
+
+// UpdateRequestParameters is the set of parameters to be passed to the
+// Update method.
+type UpdateRequestParameters struct {
+	// Command is either UpdateCheck or UpdateDownload.
+	Command UpdateCommand `json:"command"`
+
+	// Path is where to download the update to (optional).
+	Path string `json:"path,omitempty"`
+}
+
+// UpdateResult is the result of a call to the Update method.
+type UpdateResult struct {
+	// Update indicates whether an update is available.
+	Update bool `json:"update"`
+
+	// Version is the version available for download.
+	Version string `json:"version"`
+
+	// UserURI is the URI for downloading the update by hand.
+	UserURI string `json:"user_uri"`
+
+	// AutoURI is the URI used for downloading the update automatically.
+	AutoURI string `json:"auto_uri"`
+
+	// Hash is the hash of the update.
+	Hash string `json:"hash"`
+
+	// Path is where the update has been downloaded to.
+	Path string `json:"path"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetAltBlocksHashesResult is the result of a call to the GetAltBlocksHashes
+// method.
+type GetAltBlocksHashesResult struct {
+	// BlksHashes is the list of hashes of the blocks in alternative
+	// chains.
+	BlksHashes []string `json:"blks_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessInfoResult is the result of a call to the RPCAccessInfo method,
+// carrying the job that a paying client should mine on to earn credits.
+type RPCAccessInfoResult struct {
+	// HashingBlob is the hex-encoded blob to hash, with the nonce
+	// placed at offset `PaymentNonceOffset`.
+	HashingBlob string `json:"hashing_blob"`
+
+	// SeedHeight is the height of the block used as the seed for the
+	// RandomX dataset.
+	SeedHeight uint64 `json:"seed_height"`
+
+	// SeedHash is the hash of the block used as the seed for the
+	// RandomX dataset.
+	SeedHash string `json:"seed_hash"`
+
+	// NextSeedHash is the hash of the block that will be used as the next
+	// seed.
+	NextSeedHash string `json:"next_seed_hash"`
+
+	// Cookie identifies the job, to be sent back along with nonces.
+	Cookie uint32 `json:"cookie"`
+
+	// Diff is the difficulty that hashes must meet (lower 64 bits).
+	Diff uint64 `json:"diff"`
+
+	// WideDiff is the full difficulty that hashes must meet, as a
+	// hexadecimal string.
+	WideDiff string `json:"wide_diff"`
+
+	// CreditsPerHashFound is the number of credits awarded for each
+	// nonce found.
+	CreditsPerHashFound uint64 `json:"credits_per_hash_found"`
+
+	// Height is the height of the chain.
+	Height uint64 `json:"height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// Difficulty returns the difficulty that hashes must meet, preferring the
+// full-width one when the daemon sends it.
+func (r *RPCAccessInfoResult) Difficulty() (*big.Int, error) {
+	if r.WideDiff == "" {
+		return new(big.Int).SetUint64(r.Diff), nil
+	}
+
+	diff, ok := new(big.Int).SetString(
+		strings.TrimPrefix(r.WideDiff, "0x"), 16,
+	)
+	if !ok {
+		return nil, fmt.Errorf("invalid wide difficulty %q", r.WideDiff)
+	}
+
+	return diff, nil
+}
+
+type RPCAccessSubmitNonceRequestParameters struct {
+	// Client is the signature identifying the paying client (see
+	// `Signer`).
+	Client string `json:"client,omitempty"`
+
+	// Nonce is the nonce that, placed in the hashing blob, makes its
+	// hash meet the difficulty.
+	Nonce uint32 `json:"nonce"`
+
+	// Cookie is the cookie of the job that the nonce has been found for.
+	Cookie uint32 `json:"cookie"`
+}
+
+// RPCAccessSubmitNonceResult is the result of a call to the
+// RPCAccessSubmitNonce method.
+type RPCAccessSubmitNonceResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+type RPCAccessPayRequestParameters struct {
+	// Client is the signature identifying the paying client (see
+	// `Signer`).
+	Client string `json:"client,omitempty"`
+
+	// PayingFor describes what the payment is for.
+	PayingFor string `json:"paying_for"`
+
+	// Payment is the number of credits to pay.
+	Payment uint64 `json:"payment"`
+}
+
+// RPCAccessPayResult is the result of a call to the RPCAccessPay method.
+type RPCAccessPayResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessDataResult is the result of a call to the RPCAccessData method.
+type RPCAccessDataResult struct {
+	// Entries contains the accounting of each of the paying clients.
+	Entries []struct {
+		Client         string `json:"client"`
+		Balance        uint64 `json:"balance"`
+		LastUpdateTime uint64 `json:"last_update_time"`
+		CreditsTotal   uint64 `json:"credits_total"`
+		CreditsUsed    uint64 `json:"credits_used"`
+		NoncesGood     uint64 `json:"nonces_good"`
+		NoncesStale    uint64 `json:"nonces_stale"`
+		NoncesBad      uint64 `json:"nonces_bad"`
+		NoncesDupe     uint64 `json:"nonces_dupe"`
+	} `json:"entries"`
+
+	// Hashrate is the hashrate contributed by the paying clients.
+	Hashrate uint32 `json:"hashrate"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// RPCAccessAccountResult is the result of a call to the RPCAccessAccount
+// method.
+type RPCAccessAccountResult struct {
+	RPCResultFooter `json:",inline"`
 }
@@ -0,0 +1,186 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// ErrorCodeParseError indicates that the server received invalid
+	// JSON.
+	//
+	ErrorCodeParseError = -32700
+
+	// ErrorCodeInvalidRequest indicates that the JSON sent is not a valid
+	// request object.
+	//
+	ErrorCodeInvalidRequest = -32600
+
+	// ErrorCodeMethodNotFound indicates that the method does not exist or
+	// is not available.
+	//
+	// ps.: this is also what `monerod` replies with when a restricted
+	// method is invoked against a node serving a restricted RPC port.
+	//
+	ErrorCodeMethodNotFound = -32601
+
+	// ErrorCodeInvalidParams indicates that the method parameters are not
+	// valid.
+	//
+	ErrorCodeInvalidParams = -32602
+
+	// ErrorCodeInternalError indicates an internal JSONRPC error.
+	//
+	ErrorCodeInternalError = -32603
+)
+
+// bodySnippetSize is the maximum number of bytes from the body of a non-2xx
+// response that gets retained in an HTTPStatusError.
+const bodySnippetSize = 512
+
+var (
+	// ErrParseError matches any RPCError with code ErrorCodeParseError.
+	//
+	ErrParseError = &RPCError{
+		Code: ErrorCodeParseError, Message: "parse error",
+	}
+
+	// ErrInvalidRequest matches any RPCError with code
+	// ErrorCodeInvalidRequest.
+	//
+	ErrInvalidRequest = &RPCError{
+		Code: ErrorCodeInvalidRequest, Message: "invalid request",
+	}
+
+	// ErrMethodNotFound matches any RPCError with code
+	// ErrorCodeMethodNotFound.
+	//
+	ErrMethodNotFound = &RPCError{
+		Code: ErrorCodeMethodNotFound, Message: "method not found",
+	}
+
+	// ErrInvalidParams matches any RPCError with code
+	// ErrorCodeInvalidParams.
+	//
+	ErrInvalidParams = &RPCError{
+		Code: ErrorCodeInvalidParams, Message: "invalid params",
+	}
+
+	// ErrInternalError matches any RPCError with code
+	// ErrorCodeInternalError.
+	//
+	ErrInternalError = &RPCError{
+		Code: ErrorCodeInternalError, Message: "internal error",
+	}
+
+	// ErrUnauthorized matches any HTTPStatusError with a 401 status code,
+	// i.e., missing or wrong `--rpc-login` credentials.
+	//
+	ErrUnauthorized = &HTTPStatusError{StatusCode: 401}
+
+	// ErrForbidden matches any HTTPStatusError with a 403 status code.
+	//
+	ErrForbidden = &HTTPStatusError{StatusCode: 403}
+
+	// ErrNotFound matches any HTTPStatusError with a 404 status code,
+	// which is what `monerod` replies with when a restricted "raw"
+	// endpoint is hit on a node serving a restricted RPC port.
+	//
+	ErrNotFound = &HTTPStatusError{StatusCode: 404}
+)
+
+// RPCError is the error returned when the server fills the `error` object of
+// a JSONRPC response envelope.
+//
+// Values of this type can be used as sentinels: `errors.Is(err, target)`
+// reports true when `target` is an `*RPCError` with the same code, and either
+// no method or the same method.
+type RPCError struct {
+	// Method is the JSONRPC method that has been invoked.
+	//
+	Method string
+
+	// Code is the error code sent by the server - see the `ErrorCode*`
+	// constants in this package, as well as those from the `daemon` and
+	// `wallet` packages.
+	//
+	Code int
+
+	// Message is the human readable description of the error as sent by
+	// the server.
+	//
+	Message string
+}
+
+// Error implements the error interface.
+func (e *RPCError) Error() string {
+	if e.Method == "" {
+		return fmt.Sprintf("rpc error: code=%d message=%s",
+			e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("rpc error: method=%s code=%d message=%s",
+		e.Method, e.Code, e.Message)
+}
+
+// Is reports whether `target` is an RPCError sentinel matching this error.
+func (e *RPCError) Is(target error) bool {
+	t, ok := target.(*RPCError)
+	if !ok {
+		return false
+	}
+
+	return t.Code == e.Code && (t.Method == "" || t.Method == e.Method)
+}
+
+// HTTPStatusError is the error returned when the server replies with a
+// non-2xx status code.
+//
+// Values of this type can be used as sentinels: `errors.Is(err, target)`
+// reports true when `target` is an `*HTTPStatusError` with the same status
+// code.
+type HTTPStatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	//
+	StatusCode int
+
+	// Status is the status line of the response (e.g., "403 Forbidden").
+	//
+	Status string
+
+	// Body contains up to the first 512 bytes of the response body.
+	//
+	Body string
+}
+
+// Error implements the error interface.
+func (e *HTTPStatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("non-2xx status code: %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("non-2xx status code: %d body: %q",
+		e.StatusCode, e.Body)
+}
+
+// Is reports whether `target` is an HTTPStatusError sentinel with the same
+// status code.
+func (e *HTTPStatusError) Is(target error) bool {
+	t, ok := target.(*HTTPStatusError)
+	if !ok {
+		return false
+	}
+
+	return t.StatusCode == e.StatusCode
+}
+
+// ErrorCode retrieves the JSONRPC error code from any RPCError found in the
+// chain of `err`, returning false if there's none.
+func ErrorCode(err error) (int, bool) {
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) {
+		return 0, false
+	}
+
+	return rpcErr.Code, true
+}
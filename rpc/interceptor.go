@@ -0,0 +1,151 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+)
+
+// CallKind is the kind of request that a Call is made through.
+type CallKind int
+
+const (
+	// CallJSONRPC is a call to a method under the JSONRPC endpoint.
+	CallJSONRPC CallKind = iota
+
+	// CallRaw is a request to a "raw" JSON endpoint.
+	CallRaw
+
+	// CallBinary is a request to an endpoint speaking epee's binary
+	// format.
+	CallBinary
+
+	// CallBatch is a batch of calls to methods under the JSONRPC endpoint
+	// (see `JSONRPCBatch`), whose Params are the `[]*BatchCall` and
+	// Method the methods invoked, comma-separated.
+	CallBatch
+)
+
+// String returns a human readable representation of the kind of call.
+func (k CallKind) String() string {
+	switch k {
+	case CallJSONRPC:
+		return "jsonrpc"
+	case CallRaw:
+		return "raw"
+	case CallBinary:
+		return "binary"
+	case CallBatch:
+		return "batch"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(k))
+}
+
+// Call is a single request made by the client, as seen by interceptors.
+type Call struct {
+	// Kind is the kind of request being made.
+	//
+	Kind CallKind
+
+	// Method is the JSONRPC method (for CallJSONRPC) or the endpoint
+	// (e.g., "/get_height") being hit.
+	//
+	Method string
+
+	// Params is the set of parameters to send, if any.
+	//
+	Params interface{}
+
+	// Result is where the response gets decoded to.
+	//
+	Result interface{}
+
+	// BytesOut is the size of the body of the request, filled once the
+	// request has been made.
+	//
+	BytesOut int64
+
+	// BytesIn is the size of the body of the response, filled once the
+	// response has been read.
+	//
+	BytesIn int64
+}
+
+// methods gives the JSONRPC methods or endpoints invoked by the call: those
+// of every call of a batch, or its own otherwise.
+func (call *Call) methods() []string {
+	calls, ok := call.Params.([]*BatchCall)
+	if call.Kind != CallBatch || !ok {
+		return []string{call.Method}
+	}
+
+	methods := make([]string, len(calls))
+	for i, c := range calls {
+		methods[i] = c.Method
+	}
+
+	return methods
+}
+
+// Invoker performs a call, filling in its result.
+type Invoker func(ctx context.Context, call *Call) error
+
+// Interceptor wraps every call made by a client, being able to inspect and
+// mutate the call before handing it to `next` (the rest of the chain, ending
+// with the actual request), inspect and mutate the result and error
+// afterwards, or short-circuit the call altogether by not invoking `next`.
+//
+// When retries are enabled (see `WithRetry`), interceptors see each attempt.
+type Interceptor func(ctx context.Context, call *Call, next Invoker) error
+
+// WithInterceptors is a functional option for providing interceptors that
+// calls made via `JSONRPC`, `RawRequest`, `BinaryRequest` and
+// `JSONRPCBatch` go through, the first one being the outermost.
+//
+// ps.: a batch goes through as a single call (see `CallBatch`), unless sent
+// one call at a time due to the server not supporting them.
+func WithInterceptors(v ...Interceptor) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Interceptors = append(o.Interceptors, v...)
+	}
+}
+
+// chain builds the invoker that runs `interceptors` around `invoker`.
+func chain(interceptors []Interceptor, invoker Invoker) Invoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoker
+
+		invoker = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+
+	return invoker
+}
+
+// invoke performs the actual request for `call`, at the end of the chain of
+// interceptors.
+func (c *Client) invoke(ctx context.Context, call *Call) error {
+	switch call.Kind {
+	case CallJSONRPC:
+		return c.jsonrpc(ctx, call)
+	case CallRaw:
+		return c.rawRequest(ctx, call)
+	case CallBinary:
+		return c.binaryRequest(ctx, call)
+	case CallBatch:
+		return c.jsonrpcBatch(ctx, call)
+	}
+
+	return fmt.Errorf("unknown kind of call %s", call.Kind)
+}
+
+// do performs `call` through the interceptors, retrying according to the
+// client's retry policy, if any.
+func (c *Client) do(ctx context.Context, call Call) error {
+	return c.withRetry(ctx, &call, func() error {
+		attempt := call
+
+		return c.invoker(ctx, &attempt)
+	})
+}
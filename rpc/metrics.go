@@ -0,0 +1,70 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/duggavo/go-monero/metrics"
+)
+
+// WithMetrics is a functional option for recording metrics about every call
+// made by the client (including each retry) to `recorder`.
+func WithMetrics(recorder metrics.RPCRecorder) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Interceptors = append(
+			[]Interceptor{metricsInterceptor(recorder)},
+			o.Interceptors...,
+		)
+	}
+}
+
+// metricsInterceptor builds the interceptor that reports each call to
+// `recorder`.
+func metricsInterceptor(recorder metrics.RPCRecorder) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		start := time.Now()
+		err := next(ctx, call)
+
+		recorder.ObserveRPCCall(metrics.RPCCall{
+			Kind:       call.Kind.String(),
+			Method:     call.Method,
+			ErrorClass: errorClass(err, call.Result),
+			Duration:   time.Since(start),
+			BytesOut:   call.BytesOut,
+			BytesIn:    call.BytesIn,
+		})
+
+		return err
+	}
+}
+
+// errorClass classifies the outcome of a call for metrics purposes (see the
+// `metrics.ErrorClass*` constants), giving an empty class for successful
+// ones.
+func errorClass(err error, result interface{}) string {
+	if err == nil {
+		if reporter, ok := result.(statusReporter); ok && reporter.Err() != nil {
+			return metrics.ErrorClassStatus
+		}
+
+		return ""
+	}
+
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return metrics.ErrorClassCanceled
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return metrics.ErrorClassRPC
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return metrics.ErrorClassHTTP
+	}
+
+	return metrics.ErrorClassTransport
+}
@@ -0,0 +1,16 @@
+// Package pool provides a Requester that spreads requests over several nodes,
+// routing each call to the healthiest one, failing over to the next when a
+// node can't serve it, and optionally requiring a quorum of nodes to agree
+// on the results of critical reads.
+//
+// Being a Requester, it's a drop-in for the one passed to both
+// `daemon.NewClient` and `wallet.NewClient`:
+//
+//	p, _ := pool.New([]pool.Node{nodeA, nodeB, nodeC},
+//		pool.WithQuorum(2, "get_block_header_by_height"),
+//	)
+//	go p.Run(ctx, 30*time.Second)
+//
+//	client := daemon.NewClient(p)
+//
+package pool
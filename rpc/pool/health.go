@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/duggavo/go-monero/rpc/daemon"
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+// Health is the state of a node as of its last health check, updated as well
+// whenever a call to it fails.
+//
+type Health struct {
+	// Height is the height of the chain of the node.
+	//
+	Height uint64
+
+	// Synchronized indicates whether the node reports being in sync with
+	// the network.
+	//
+	Synchronized bool
+
+	// Latency is how long the health check took.
+	//
+	Latency time.Duration
+
+	// Err is the error from the last health check or failed call, if
+	// any.
+	//
+	Err error
+
+	// CheckedAt is when the node has last been checked.
+	//
+	CheckedAt time.Time
+}
+
+// HealthCheck probes a node, filling in its height and whether it's
+// synchronized. Latency and time of the check are filled by the pool.
+//
+type HealthCheck func(ctx context.Context, node Node) (Health, error)
+
+// DaemonHealthCheck checks a `monerod` node via `get_info`.
+//
+func DaemonHealthCheck(ctx context.Context, node Node) (Health, error) {
+	info, err := daemon.NewClient(node).GetInfo(ctx)
+	if err != nil {
+		return Health{}, fmt.Errorf("get info: %w", err)
+	}
+
+	return Health{
+		Height:       info.Height,
+		Synchronized: info.Synchronized && !info.BusySyncing,
+	}, nil
+}
+
+// WalletHealthCheck checks a `monero-wallet-rpc` node via `get_height`,
+// always considering it synchronized.
+//
+func WalletHealthCheck(ctx context.Context, node Node) (Health, error) {
+	res, err := wallet.NewClient(node).GetHeight(ctx)
+	if err != nil {
+		return Health{}, fmt.Errorf("get height: %w", err)
+	}
+
+	return Health{
+		Height:       res.Height,
+		Synchronized: true,
+	}, nil
+}
+
+// CheckHealth checks every node concurrently, updating their health.
+//
+func (p *Pool) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, n := range p.nodes {
+		wg.Add(1)
+
+		go func(n *member) {
+			defer wg.Done()
+
+			start := time.Now()
+			health, err := p.healthCheck(ctx, n.Node)
+
+			health.Latency = time.Since(start)
+			health.CheckedAt = time.Now()
+			health.Err = err
+
+			p.mu.Lock()
+			n.health = health
+			p.mu.Unlock()
+		}(n)
+	}
+
+	wg.Wait()
+}
+
+// Run checks the health of every node right away and then every
+// `interval`, until `ctx` is done.
+//
+func (p *Pool) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Health returns the health of each node, in the order they've been given
+// to `New`.
+//
+func (p *Pool) Health() []Health {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	health := make([]Health, len(p.nodes))
+	for i, n := range p.nodes {
+		health[i] = n.health
+	}
+
+	return health
+}
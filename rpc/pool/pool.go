@@ -0,0 +1,410 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/duggavo/go-monero/rpc"
+	"github.com/duggavo/go-monero/rpc/daemon"
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+// ErrNoNodes is the error returned when instantiating a pool without any
+// node.
+var ErrNoNodes = errors.New("no nodes")
+
+// Node is a node that the pool can send requests to (e.g., `*rpc.Client`).
+//
+type Node interface {
+	daemon.Requester
+}
+
+// member is a node of the pool along with what the pool knows about it.
+//
+type member struct {
+	Node
+
+	// index is the position of the node in the list given to `New`,
+	// used for breaking ties.
+	//
+	index int
+
+	// health is the last known health of the node, guarded by the
+	// pool's mutex.
+	//
+	health Health
+}
+
+// Pool is a Requester that routes each call to the healthiest of its nodes,
+// failing over to the next one when a node is unreachable, fails with a
+// server error, or is too busy to serve the call - or, for calls that must
+// not be submitted twice (e.g., `transfer`), only when the node couldn't be
+// connected to.
+//
+type Pool struct {
+	nodes []*member
+
+	healthCheck HealthCheck
+	maxLag      uint64
+
+	quorum        int
+	quorumMethods map[string]bool
+
+	// mu guards the health of the members.
+	//
+	mu sync.RWMutex
+}
+
+// options is a set of options that can be overridden to tweak the pool's
+// behavior.
+//
+type options struct {
+	HealthCheck   HealthCheck
+	MaxLag        uint64
+	Quorum        int
+	QuorumMethods []string
+}
+
+// Option defines a functional option for overriding optional pool
+// configuration parameters.
+//
+type Option func(o *options)
+
+// WithHealthCheck is a functional option for providing the function used for
+// checking the health of the nodes (`DaemonHealthCheck` by default - use
+// `WalletHealthCheck` for a pool of wallets).
+//
+func WithHealthCheck(v HealthCheck) func(o *options) {
+	return func(o *options) {
+		o.HealthCheck = v
+	}
+}
+
+// WithMaxLag is a functional option for setting how many blocks behind the
+// highest node a node can be before being considered unhealthy (2 by
+// default).
+//
+func WithMaxLag(v uint64) func(o *options) {
+	return func(o *options) {
+		o.MaxLag = v
+	}
+}
+
+// WithQuorum is a functional option for requiring that `n` nodes agree on
+// the result of calls to `methods` (JSONRPC methods or raw endpoints) before
+// handing it back. Without any method, it applies to `CriticalReads`.
+//
+func WithQuorum(n int, methods ...string) func(o *options) {
+	return func(o *options) {
+		o.Quorum = n
+		o.QuorumMethods = methods
+	}
+}
+
+// CriticalReads is the list of methods that a quorum applies to when none is
+// given to `WithQuorum`.
+var CriticalReads = []string{
+	"get_block_header_by_hash",
+	"get_block_header_by_height",
+	"get_block",
+	"on_get_block_hash",
+}
+
+// New instantiates a pool routing requests to `nodes`.
+//
+// Until the first health check (see `CheckHealth` and `Run`), nodes are
+// tried in the order given.
+//
+func New(nodes []Node, opts ...Option) (*Pool, error) {
+	if len(nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+
+	options := &options{
+		HealthCheck: DaemonHealthCheck,
+		MaxLag:      2,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.Quorum > len(nodes) {
+		return nil, fmt.Errorf("quorum of %d with only %d nodes",
+			options.Quorum, len(nodes))
+	}
+
+	p := &Pool{
+		healthCheck:   options.HealthCheck,
+		maxLag:        options.MaxLag,
+		quorum:        options.Quorum,
+		quorumMethods: map[string]bool{},
+	}
+
+	for i, node := range nodes {
+		p.nodes = append(p.nodes, &member{Node: node, index: i})
+	}
+
+	methods := options.QuorumMethods
+	if len(methods) == 0 {
+		methods = CriticalReads
+	}
+
+	if p.quorum > 1 {
+		for _, method := range methods {
+			p.quorumMethods[method] = true
+		}
+	}
+
+	return p, nil
+}
+
+// JSONRPC calls `method` on the healthiest node, failing over to the others
+// as needed.
+//
+func (p *Pool) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return p.do(ctx, method, result,
+		func(ctx context.Context, n Node, result interface{}) error {
+			return n.JSONRPC(ctx, method, params, result)
+		},
+	)
+}
+
+// RawRequest hits `endpoint` on the healthiest node, failing over to the
+// others as needed.
+//
+func (p *Pool) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(ctx, endpoint, response,
+		func(ctx context.Context, n Node, response interface{}) error {
+			return n.RawRequest(ctx, endpoint, params, response)
+		},
+	)
+}
+
+// BinaryRequest hits the binary endpoint `endpoint` on the healthiest node
+// supporting binary requests, failing over to the others as needed.
+//
+func (p *Pool) BinaryRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(ctx, endpoint, response,
+		func(ctx context.Context, n Node, response interface{}) error {
+			requester, ok := n.(daemon.BinaryRequester)
+			if !ok {
+				return daemon.ErrBinaryUnsupported
+			}
+
+			return requester.BinaryRequest(ctx, endpoint, params, response)
+		},
+	)
+}
+
+// call performs a request against a single node.
+//
+type call func(ctx context.Context, n Node, result interface{}) error
+
+// do performs a request, either via failover or quorum depending on
+// `method`.
+//
+func (p *Pool) do(
+	ctx context.Context, method string, result interface{}, fn call,
+) error {
+	if p.quorumMethods[method] {
+		return p.doQuorum(ctx, result, fn)
+	}
+
+	return p.doFailover(ctx, method, result, fn)
+}
+
+// doFailover tries each node, from the healthiest to the least healthy,
+// until one serves the request.
+//
+func (p *Pool) doFailover(
+	ctx context.Context, method string, result interface{}, fn call,
+) error {
+	var errs []error
+
+	for _, n := range p.ranked() {
+		reset(result)
+
+		err := fn(ctx, n.Node, result)
+		if err == nil {
+			err = busy(result)
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		if ctx.Err() != nil || !shouldFailover(method, err) {
+			return err
+		}
+
+		p.markFailed(n, err)
+		errs = append(errs, fmt.Errorf("node %d: %w", n.index, err))
+	}
+
+	return &AllNodesFailedError{Errs: errs}
+}
+
+// ranked returns the members sorted from the healthiest to the least
+// healthy: healthy ones first (reachable, synchronized, and not lagging
+// behind), then the highest, then the fastest.
+//
+func (p *Pool) ranked() []*member {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	var best uint64
+	for _, n := range p.nodes {
+		if n.health.Err == nil && n.health.Height > best {
+			best = n.health.Height
+		}
+	}
+
+	healthy := func(n *member) bool {
+		if n.health.CheckedAt.IsZero() {
+			return n.health.Err == nil
+		}
+
+		return n.health.Err == nil && n.health.Synchronized &&
+			n.health.Height+p.maxLag >= best
+	}
+
+	nodes := make([]*member, len(p.nodes))
+	copy(nodes, p.nodes)
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+
+		if ha, hb := healthy(a), healthy(b); ha != hb {
+			return ha
+		}
+
+		if a.health.Height != b.health.Height {
+			return a.health.Height > b.health.Height
+		}
+
+		return a.health.Latency < b.health.Latency
+	})
+
+	return nodes
+}
+
+// markFailed records that a call to `n` failed, so that it's tried last
+// until its next health check.
+//
+func (p *Pool) markFailed(n *member, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n.health.Err = err
+}
+
+// AllNodesFailedError is the error returned when no node was able to serve a
+// request.
+//
+type AllNodesFailedError struct {
+	// Errs contains the error from each node tried.
+	//
+	Errs []error
+}
+
+// Error implements the error interface.
+func (e *AllNodesFailedError) Error() string {
+	return fmt.Sprintf("all %d nodes failed, last: %v",
+		len(e.Errs), e.Errs[len(e.Errs)-1])
+}
+
+// Unwrap returns the error from the last node tried.
+func (e *AllNodesFailedError) Unwrap() error {
+	return e.Errs[len(e.Errs)-1]
+}
+
+// shouldFailover reports whether a request to `method` that failed with
+// `err` is worth trying against another node, i.e., the node is at fault
+// (see `nodeFailed`).
+//
+// Non-idempotent methods (see `rpc.IsNonIdempotent`) only fail over when the
+// request provably never reached the node, as it may otherwise have acted on
+// it (e.g., paid) before failing or timing out.
+//
+func shouldFailover(method string, err error) bool {
+	if errors.Is(err, daemon.ErrBinaryUnsupported) {
+		return true
+	}
+
+	if rpc.IsNonIdempotent(method) {
+		return dialFailed(err)
+	}
+
+	return nodeFailed(err)
+}
+
+// nodeFailed reports whether a request that failed with `err` did so because
+// of the node, i.e., the node couldn't be reached, failed with a server
+// error, or was too busy to serve it - as opposed to the request itself
+// being invalid.
+//
+func nodeFailed(err error) bool {
+	if errors.Is(err, daemon.ErrBusy) || errors.Is(err, wallet.ErrBusy) ||
+		errors.Is(err, daemon.ErrBinaryUnsupported) {
+		return true
+	}
+
+	var rpcErr *rpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return false
+	}
+
+	var statusErr *rpc.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+
+	return true
+}
+
+// dialFailed reports whether `err` comes from failing to establish the
+// connection to the node, before anything was sent to it.
+//
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// busy returns a `*daemon.StatusError` if `result` carries a footer whose
+// status is `BUSY`.
+//
+func busy(result interface{}) error {
+	footered, ok := result.(interface {
+		Footer() *daemon.RPCResultFooter
+	})
+	if !ok || footered.Footer().Status != daemon.StatusBusy {
+		return nil
+	}
+
+	return &daemon.StatusError{Status: daemon.StatusBusy}
+}
+
+// reset zeroes what `result` points to so that nothing from a failed
+// attempt leaks into the next one.
+//
+func reset(result interface{}) {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+}
@@ -0,0 +1,323 @@
+package pool_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/rpc"
+	"github.com/duggavo/go-monero/rpc/daemon"
+	"github.com/duggavo/go-monero/rpc/pool"
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+// fakeNode replies to `get_info` with its height and to any other call with
+// `body`, or fails with `err`.
+type fakeNode struct {
+	height uint64
+	body   string
+	err    error
+
+	mu    sync.Mutex
+	calls int
+}
+
+func (n *fakeNode) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	if method == "get_info" {
+		return json.Unmarshal([]byte(`{"status":"OK","synchronized":true,"height":`+
+			jsonUint(n.height)+`}`), result)
+	}
+
+	n.mu.Lock()
+	n.calls++
+	n.mu.Unlock()
+
+	if n.err != nil {
+		return n.err
+	}
+
+	return json.Unmarshal([]byte(n.body), result)
+}
+
+func (n *fakeNode) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return n.JSONRPC(ctx, endpoint, params, response)
+}
+
+func (n *fakeNode) Calls() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls
+}
+
+func jsonUint(v uint64) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func header(hash string) string {
+	return `{"status":"OK","credits":` + jsonUint(uint64(len(hash))) +
+		`,"block_header":{"hash":"` + hash + `"}}`
+}
+
+func TestPoolFailover(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name      string
+		first     *fakeNode
+		failsOver bool
+	}{
+		{
+			name:      "transport error",
+			first:     &fakeNode{err: errors.New("connection refused")},
+			failsOver: true,
+		},
+
+		{
+			name:      "busy status",
+			first:     &fakeNode{body: `{"status":"BUSY"}`},
+			failsOver: true,
+		},
+
+		{
+			name:      "busy error",
+			first:     &fakeNode{err: daemon.ErrBusy},
+			failsOver: true,
+		},
+
+		{
+			name:      "server error",
+			first:     &fakeNode{err: &rpc.HTTPStatusError{StatusCode: 502}},
+			failsOver: true,
+		},
+
+		{
+			name:  "rpc error",
+			first: &fakeNode{err: rpc.ErrInvalidParams},
+		},
+
+		{
+			name:  "unauthorized",
+			first: &fakeNode{err: rpc.ErrUnauthorized},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			second := &fakeNode{body: header("aa")}
+
+			p, err := pool.New([]pool.Node{tc.first, second})
+			require.NoError(t, err)
+
+			res, err := daemon.NewClient(p).GetBlockHeaderByHeight(
+				context.Background(), 1,
+			)
+			if !tc.failsOver {
+				assert.Error(t, err)
+				assert.Equal(t, 0, second.Calls())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "aa", res.BlockHeader.Hash)
+
+			// the failed node is now tried last.
+			_, err = daemon.NewClient(p).GetBlockHeaderByHeight(
+				context.Background(), 1,
+			)
+			require.NoError(t, err)
+			assert.Equal(t, 1, tc.first.Calls())
+		})
+	}
+}
+
+// newHTTPNode instantiates a client for a server handling requests with
+// `handler`, giving up on requests after a short timeout.
+func newHTTPNode(t *testing.T, handler http.HandlerFunc) *rpc.Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(
+		&http.Client{Timeout: 100 * time.Millisecond},
+	))
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestPoolNonIdempotentFailover(t *testing.T) {
+	t.Parallel()
+
+	transfer := wallet.TransferParameters{
+		Destinations: []wallet.Destination{{Address: "addr", Amount: 1}},
+	}
+
+	var secondCalls int32
+
+	second := newHTTPNode(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&secondCalls, 1)
+		_, _ = w.Write([]byte(`{"result":{"tx_hash":"hash"}}`))
+	})
+
+	t.Run("timeout after the request was sent", func(t *testing.T) {
+		atomic.StoreInt32(&secondCalls, 0)
+
+		received := make(chan string, 1)
+
+		first := newHTTPNode(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			received <- string(body)
+
+			<-r.Context().Done()
+		})
+
+		p, err := pool.New([]pool.Node{first, second})
+		require.NoError(t, err)
+
+		_, err = wallet.NewClient(p).Transfer(context.Background(), transfer)
+		assert.Error(t, err)
+		assert.True(t, strings.Contains(<-received, `"transfer"`))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&secondCalls))
+	})
+
+	t.Run("dial failed", func(t *testing.T) {
+		atomic.StoreInt32(&secondCalls, 0)
+
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		first, err := rpc.NewClient(server.URL)
+		require.NoError(t, err)
+
+		p, err := pool.New([]pool.Node{first, second})
+		require.NoError(t, err)
+
+		res, err := wallet.NewClient(p).Transfer(context.Background(), transfer)
+		require.NoError(t, err)
+		assert.Equal(t, "hash", res.TxHash)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&secondCalls))
+	})
+}
+
+func TestPoolAllNodesFailed(t *testing.T) {
+	t.Parallel()
+
+	p, err := pool.New([]pool.Node{
+		&fakeNode{err: errors.New("a")},
+		&fakeNode{err: errors.New("b")},
+	})
+	require.NoError(t, err)
+
+	_, err = daemon.NewClient(p).GetBlockHeaderByHeight(
+		context.Background(), 1,
+	)
+
+	var allErr *pool.AllNodesFailedError
+	require.True(t, errors.As(err, &allErr))
+	assert.Len(t, allErr.Errs, 2)
+}
+
+func TestPoolRoutesToHighest(t *testing.T) {
+	t.Parallel()
+
+	lagging := &fakeNode{height: 10, body: header("lagging")}
+	highest := &fakeNode{height: 20, body: header("highest")}
+
+	p, err := pool.New([]pool.Node{lagging, highest})
+	require.NoError(t, err)
+
+	p.CheckHealth(context.Background())
+
+	health := p.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, uint64(10), health[0].Height)
+	assert.True(t, health[1].Synchronized)
+
+	res, err := daemon.NewClient(p).GetBlockHeaderByHeight(
+		context.Background(), 1,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "highest", res.BlockHeader.Hash)
+}
+
+func TestPoolQuorum(t *testing.T) {
+	t.Parallel()
+
+	p, err := pool.New([]pool.Node{
+		&fakeNode{body: header("bad")},
+		&fakeNode{body: header("good")},
+		&fakeNode{body: header("good")},
+	}, pool.WithQuorum(2))
+	require.NoError(t, err)
+
+	res, err := daemon.NewClient(p).GetBlockHeaderByHeight(
+		context.Background(), 1,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "good", res.BlockHeader.Hash)
+
+	p, err = pool.New([]pool.Node{
+		&fakeNode{body: header("a")},
+		&fakeNode{body: header("b")},
+		&fakeNode{err: errors.New("down")},
+	}, pool.WithQuorum(2))
+	require.NoError(t, err)
+
+	_, err = daemon.NewClient(p).GetBlockHeaderByHeight(
+		context.Background(), 1,
+	)
+
+	var qerr *pool.NoQuorumError
+	require.True(t, errors.As(err, &qerr))
+	assert.Equal(t, 1, qerr.Agreeing)
+	assert.Len(t, qerr.Errs, 1)
+}
+
+func TestPoolQuorumNodeRelativeFields(t *testing.T) {
+	t.Parallel()
+
+	// the same block seen from nodes a block apart, one of them serving
+	// from a bootstrap daemon.
+	//
+	p, err := pool.New([]pool.Node{
+		&fakeNode{body: `{"status":"OK","block_header":` +
+			`{"hash":"h","height":10,"depth":5}}`},
+		&fakeNode{body: `{"status":"OK","untrusted":true,"block_header":` +
+			`{"hash":"h","height":10,"depth":6}}`},
+	}, pool.WithQuorum(2))
+	require.NoError(t, err)
+
+	res, err := daemon.NewClient(p).GetBlockHeaderByHeight(
+		context.Background(), 10,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "h", res.BlockHeader.Hash)
+	assert.Contains(t, []uint64{5, 6}, res.BlockHeader.Depth)
+}
+
+func TestNewWithoutNodes(t *testing.T) {
+	t.Parallel()
+
+	_, err := pool.New(nil)
+	assert.True(t, errors.Is(err, pool.ErrNoNodes))
+}
@@ -0,0 +1,175 @@
+package pool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/duggavo/go-monero/rpc/daemon"
+)
+
+// NoQuorumError is the error returned when not enough nodes agree on the
+// result of a call subject to a quorum.
+//
+type NoQuorumError struct {
+	// Quorum is the number of nodes required to agree.
+	//
+	Quorum int
+
+	// Agreeing is the largest number of nodes that agreed on a result.
+	//
+	Agreeing int
+
+	// Errs contains the errors from the nodes that failed to reply.
+	//
+	Errs []error
+}
+
+// Error implements the error interface.
+func (e *NoQuorumError) Error() string {
+	return fmt.Sprintf("no quorum: %d of %d nodes agreeing, %d failed",
+		e.Agreeing, e.Quorum, len(e.Errs))
+}
+
+// doQuorum sends the request to every node concurrently, handing back the
+// first result that `p.quorum` nodes agree on.
+//
+// Results are compared disregarding the fields that are specific to the
+// node serving them (see `fingerprint`).
+//
+func (p *Pool) doQuorum(
+	ctx context.Context, result interface{}, fn call,
+) error {
+	rv := reflect.ValueOf(result)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("quorum requires a non-nil pointer result, got %T",
+			result)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type response struct {
+		value reflect.Value
+		key   string
+		err   error
+	}
+
+	nodes := p.ranked()
+	responses := make(chan response, len(nodes))
+
+	for _, n := range nodes {
+		go func(n *member) {
+			value := reflect.New(rv.Elem().Type())
+
+			err := fn(ctx, n.Node, value.Interface())
+			if err == nil {
+				err = busy(value.Interface())
+			}
+
+			if err != nil {
+				if ctx.Err() == nil && nodeFailed(err) {
+					p.markFailed(n, err)
+				}
+
+				responses <- response{err: fmt.Errorf(
+					"node %d: %w", n.index, err,
+				)}
+				return
+			}
+
+			key, err := fingerprint(value)
+			responses <- response{value: value, key: key, err: err}
+		}(n)
+	}
+
+	qerr := &NoQuorumError{Quorum: p.quorum}
+	agreeing := map[string]int{}
+
+	for range nodes {
+		r := <-responses
+		if r.err != nil {
+			qerr.Errs = append(qerr.Errs, r.err)
+			continue
+		}
+
+		agreeing[r.key]++
+		if agreeing[r.key] > qerr.Agreeing {
+			qerr.Agreeing = agreeing[r.key]
+		}
+
+		if agreeing[r.key] >= p.quorum {
+			rv.Elem().Set(r.value.Elem())
+			return nil
+		}
+	}
+
+	return qerr
+}
+
+// blockHeaderType is the type of the block headers found in the results of
+// most critical reads, whose depth is relative to the height of the node.
+var blockHeaderType = reflect.TypeOf(daemon.BlockHeader{})
+
+// fingerprint gives a representation of the result pointed to by `value`
+// that's equal for equal results, regardless of the fields that depend on
+// the node serving it: the whole footer (an honest node may e.g. be
+// answering from a bootstrap daemon), and the depth of block headers, which
+// differs between nodes only a block apart.
+//
+func fingerprint(value reflect.Value) (string, error) {
+	b, err := json.Marshal(value.Interface())
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	// round-trip through JSON to get a deep copy that can be altered
+	// without touching the result handed back.
+	//
+	clone := reflect.New(value.Elem().Type())
+	if err := json.Unmarshal(b, clone.Interface()); err != nil {
+		return "", fmt.Errorf("unmarshal: %w", err)
+	}
+
+	if footered, ok := clone.Interface().(interface {
+		Footer() *daemon.RPCResultFooter
+	}); ok {
+		*footered.Footer() = daemon.RPCResultFooter{}
+	}
+
+	clearDepths(clone)
+
+	b, err = json.Marshal(clone.Interface())
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// clearDepths zeroes the depth of every block header reachable from `v`.
+//
+func clearDepths(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			clearDepths(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			clearDepths(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() == blockHeaderType {
+			v.FieldByName("Depth").SetUint(0)
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				clearDepths(v.Field(i))
+			}
+		}
+	}
+}
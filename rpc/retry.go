@@ -0,0 +1,350 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// errorCodeCoreBusy is the code that `monerod` fills JSONRPC errors with
+// when it's too busy (e.g., still syncing) to serve a request.
+const errorCodeCoreBusy = -9
+
+// nonIdempotentMethods is the set of JSONRPC methods and raw endpoints that
+// are never retried unless explicitly allowed via
+// `RetryPolicy.AllowNonIdempotent`, as submitting them twice may have side
+// effects (e.g., paying twice).
+//
+// ps.: only ever read, thus safe for concurrent use - see `IsNonIdempotent`.
+var nonIdempotentMethods = map[string]bool{
+	// daemon
+	"flush_txpool":            true,
+	"generateblocks":          true,
+	"relay_tx":                true,
+	"rpc_access_pay":          true,
+	"rpc_access_submit_nonce": true,
+	"submit_block":            true,
+	"/pop_blocks":             true,
+	"/send_raw_transaction":   true,
+	"/start_mining":           true,
+	"/stop_daemon":            true,
+
+	// wallet
+	"create_account":               true,
+	"create_address":               true,
+	"create_wallet":                true,
+	"exchange_multisig_keys":       true,
+	"finalize_multisig":            true,
+	"generate_from_keys":           true,
+	"make_multisig":                true,
+	"relay_tx_hex":                 true,
+	"restore_deterministic_wallet": true,
+	"sign_multisig":                true,
+	"submit_multisig":              true,
+	"submit_transfer":              true,
+	"sweep_all":                    true,
+	"sweep_dust":                   true,
+	"sweep_single":                 true,
+	"sweep_unmixable":              true,
+	"transfer":                     true,
+	"transfer_split":               true,
+}
+
+// IsNonIdempotent reports whether `method` (a JSONRPC method or raw
+// endpoint) may have side effects if submitted twice (e.g., paying twice),
+// and thus must not be resubmitted unless it provably never reached the
+// server.
+func IsNonIdempotent(method string) bool {
+	return nonIdempotentMethods[method]
+}
+
+// RetryClassifier decides whether a call to `method` that failed with `err`
+// should be retried.
+type RetryClassifier func(method string, err error) bool
+
+// RetryEvent describes a retry about to happen, as handed to
+// `RetryPolicy.OnRetry`.
+type RetryEvent struct {
+	// Method is the JSONRPC method or raw endpoint being retried.
+	//
+	Method string
+
+	// Attempt is the number of the attempt about to be made (the first
+	// retry being attempt 2).
+	//
+	Attempt int
+
+	// Err is the error that the previous attempt failed with.
+	//
+	Err error
+
+	// Backoff is how long the client waits before the attempt.
+	//
+	Backoff time.Duration
+}
+
+// RetryPolicy configures how a client retries failed calls, waiting an
+// exponentially increasing (and jittered) amount of time between attempts.
+//
+// Zero values for the number of attempts, the backoffs, the multiplier and
+// the classifier are replaced by those from `DefaultRetryPolicy`.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, including
+	// the first one.
+	//
+	MaxAttempts int
+
+	// InitialBackoff is the time to wait before the first retry.
+	//
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the time to wait between attempts.
+	//
+	MaxBackoff time.Duration
+
+	// Multiplier is the factor that the backoff grows by after each
+	// attempt.
+	//
+	Multiplier float64
+
+	// Jitter is the fraction (from 0 to 1) of each backoff that is
+	// randomized, so that clients don't retry in lockstep.
+	//
+	Jitter float64
+
+	// Budget caps the total time spent on a call across all of its
+	// attempts (0 for no limit other than the context's deadline).
+	//
+	Budget time.Duration
+
+	// Classifier decides which errors are worth retrying
+	// (`DefaultRetryClassifier` if nil).
+	//
+	Classifier RetryClassifier
+
+	// AllowNonIdempotent lists the non-idempotent methods (see
+	// `IsNonIdempotent`) that may be retried nonetheless.
+	//
+	AllowNonIdempotent []string
+
+	// OnRetry, if set, is called before every retry.
+	//
+	OnRetry func(RetryEvent)
+}
+
+// DefaultRetryPolicy returns the policy used when retries are enabled
+// without tweaking any of its parameters.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 250 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		Classifier:     DefaultRetryClassifier,
+	}
+}
+
+// WithRetry is a functional option for making the client retry failed calls
+// according to `policy`.
+func WithRetry(policy RetryPolicy) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		defaults := DefaultRetryPolicy()
+
+		if policy.MaxAttempts == 0 {
+			policy.MaxAttempts = defaults.MaxAttempts
+		}
+
+		if policy.InitialBackoff == 0 {
+			policy.InitialBackoff = defaults.InitialBackoff
+		}
+
+		if policy.MaxBackoff == 0 {
+			policy.MaxBackoff = defaults.MaxBackoff
+		}
+
+		if policy.Multiplier == 0 {
+			policy.Multiplier = defaults.Multiplier
+		}
+
+		if policy.Classifier == nil {
+			policy.Classifier = defaults.Classifier
+		}
+
+		o.Retry = &policy
+	}
+}
+
+// DefaultRetryClassifier retries calls that failed due to the server being
+// unreachable (i.e., a network error), replying with a 5xx or 429 status
+// code, or being busy - either via a JSONRPC error or the `status` of the
+// result. Any other error (e.g., failing to decode a response) is bound to
+// happen again, thus not retried.
+func DefaultRetryClassifier(method string, err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr.Code == errorCodeCoreBusy
+	}
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == 429
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	return isNetworkError(err)
+}
+
+// isNetworkError reports whether `err` comes from the connection to the
+// server (e.g., refused, reset, or timing out) rather than from what was
+// sent or received over it.
+func isNetworkError(err error) bool {
+	// `*url.Error` wraps whatever `http.Client.Do` failed with, and
+	// implements `net.Error` itself regardless of the cause.
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// statusReporter is implemented by results that carry a status that may
+// denote a failure even though the request succeeded (e.g., those embedding
+// `daemon.RPCResultFooter`).
+type statusReporter interface {
+	Err() error
+}
+
+// withRetry performs `call` via `do`, retrying according to the client's
+// retry policy, if any.
+func (c *Client) withRetry(
+	ctx context.Context, call *Call, do func() error,
+) error {
+	policy := c.retry
+	if policy == nil || !policy.allows(call.methods()...) {
+		return do()
+	}
+
+	method, result := call.Method, call.Result
+
+	start := time.Now()
+
+	for attempt := 1; ; attempt++ {
+		err := do()
+
+		// a failing status is only a reason for retrying, not an error
+		// at this level - the caller gets to inspect it if retries are
+		// exhausted.
+		retryErr := err
+		if retryErr == nil {
+			if reporter, ok := result.(statusReporter); ok {
+				retryErr = reporter.Err()
+			}
+		}
+
+		if retryErr == nil || attempt >= policy.MaxAttempts ||
+			!policy.Classifier(method, retryErr) {
+			return err
+		}
+
+		backoff := policy.backoff(attempt)
+		if !policy.withinBudget(ctx, start, backoff) {
+			return err
+		}
+
+		if policy.OnRetry != nil {
+			policy.OnRetry(RetryEvent{
+				Method:  method,
+				Attempt: attempt + 1,
+				Err:     retryErr,
+				Backoff: backoff,
+			})
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// allows reports whether a call to `methods` (several for a batch) may be
+// retried at all.
+func (p *RetryPolicy) allows(methods ...string) bool {
+	for _, method := range methods {
+		if IsNonIdempotent(method) && !p.allowsNonIdempotent(method) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// allowsNonIdempotent reports whether the non-idempotent `method` has been
+// explicitly allowed to be retried.
+func (p *RetryPolicy) allowsNonIdempotent(method string) bool {
+	for _, allowed := range p.AllowNonIdempotent {
+		if allowed == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff computes the time to wait after the `attempt`-th attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff) *
+		math.Pow(p.Multiplier, float64(attempt-1))
+	if backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	backoff -= backoff * p.Jitter * rand.Float64()
+
+	return time.Duration(backoff)
+}
+
+// withinBudget reports whether waiting `backoff` and retrying still fits in
+// both the retry budget and the deadline of the context.
+func (p *RetryPolicy) withinBudget(
+	ctx context.Context, start time.Time, backoff time.Duration,
+) bool {
+	next := time.Now().Add(backoff)
+
+	if p.Budget > 0 && next.After(start.Add(p.Budget)) {
+		return false
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && next.After(deadline) {
+		return false
+	}
+
+	return true
+}
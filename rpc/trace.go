@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/duggavo/go-monero/trace"
+)
+
+// WithTracer is a functional option for creating a span around every call
+// made by the client (including each retry) with `tracer`.
+//
+// Without it, spans are still created if the context of a call carries a
+// tracer (see `trace.ContextWithTracer`).
+func WithTracer(tracer trace.Tracer) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Tracer = tracer
+	}
+}
+
+// statusFooter is implemented by results carrying the `status` and
+// `untrusted` fields common to the daemon's replies (i.e., those embedding
+// `daemon.RPCResultFooter`).
+type statusFooter interface {
+	RPCStatus() (status string, untrusted bool)
+}
+
+// traceInterceptor builds the interceptor that wraps each call in a span
+// created by `tracer`, or the tracer carried by the call's context if nil.
+//
+// The tracer is handed down in the context so that the transport (e.g.,
+// `http.DigestAuthTransport`) can create spans nested under the call's.
+func (c *Client) traceInterceptor(tracer trace.Tracer) Interceptor {
+	return func(ctx context.Context, call *Call, next Invoker) error {
+		t := tracer
+		if t == nil {
+			t = trace.TracerFromContext(ctx)
+		} else {
+			ctx = trace.ContextWithTracer(ctx, t)
+		}
+
+		if t == nil {
+			return next(ctx, call)
+		}
+
+		endpoint := call.Method
+		if call.Kind == CallJSONRPC || call.Kind == CallBatch {
+			endpoint = endpointJSONRPC
+		}
+
+		ctx, span := t.Start(ctx, call.Kind.String()+" "+call.Method,
+			trace.String(trace.AttrRPCSystem, "monero"),
+			trace.String(trace.AttrRPCKind, call.Kind.String()),
+			trace.String(trace.AttrRPCMethod, call.Method),
+			trace.String(trace.AttrRPCEndpoint, endpoint),
+			trace.String(trace.AttrServerAddress, c.address.Host),
+		)
+		defer span.End()
+
+		err := next(ctx, call)
+		if err != nil {
+			span.RecordError(err)
+			return err
+		}
+
+		if footer, ok := call.Result.(statusFooter); ok {
+			status, untrusted := footer.RPCStatus()
+
+			span.SetAttributes(
+				trace.String(trace.AttrRPCStatus, status),
+				trace.Bool(trace.AttrRPCUntrusted, untrusted),
+			)
+		}
+
+		if reporter, ok := call.Result.(statusReporter); ok {
+			if err := reporter.Err(); err != nil {
+				span.RecordError(err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"net/url"
+	"strings"
+)
+
+const (
+	// schemeUnix is the scheme of addresses of servers reachable through
+	// a unix domain socket, speaking plain HTTP.
+	//
+	schemeUnix = "unix"
+
+	// schemeUnixTLS is the scheme of addresses of servers reachable
+	// through a unix domain socket, speaking HTTPS.
+	//
+	schemeUnixTLS = "unix+https"
+
+	// unixHost is the host that requests sent through a unix domain socket
+	// are addressed to (and that certificates are verified against, for
+	// TLS).
+	//
+	unixHost = "localhost"
+)
+
+// isUnixAddress tells whether `u` points at a unix domain socket.
+func isUnixAddress(u *url.URL) bool {
+	return u.Scheme == schemeUnix || u.Scheme == schemeUnixTLS
+}
+
+// parseUnixAddress splits a `unix://` (or `unix+https://`) address into the
+// path of the socket, the path prefix of the endpoints, and the HTTP address
+// that requests are made to.
+func parseUnixAddress(u *url.URL) (socket, pathPrefix string, address *url.URL) {
+	socket = u.Path
+	if i := strings.Index(socket, ":"); i >= 0 {
+		socket, pathPrefix = socket[:i], socket[i+1:]
+	}
+
+	scheme := "http"
+	if u.Scheme == schemeUnixTLS {
+		scheme = "https"
+	}
+
+	address = &url.URL{Scheme: scheme, Host: unixHost}
+
+	return socket, strings.TrimSuffix(pathPrefix, "/"), address
+}
+
+// url gives the address of `endpoint` on the server.
+func (c *Client) url(endpoint string) url.URL {
+	address := *c.address
+	address.Path = c.pathPrefix + endpoint
+
+	return address
+}
@@ -0,0 +1,107 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNothingToSign is the error returned when the transaction set handed to
+// ColdSigner has no transactions in it.
+//
+var ErrNothingToSign = errors.New("no transactions to sign")
+
+// ColdSigner coordinates a view-only wallet (connected to a daemon, able to
+// create and submit transactions but not to sign them) with a wallet holding
+// the spend key (e.g., air-gapped) for signing the transactions the former
+// creates.
+//
+type ColdSigner struct {
+	// ViewOnly is the view-only wallet that creates the unsigned
+	// transactions and submits the signed ones.
+	//
+	ViewOnly *Client
+
+	// Signer is the wallet holding the spend key.
+	//
+	Signer *Client
+
+	// Approve, if set, is handed the description of every transaction
+	// set before it's signed, aborting the signing if it errors (e.g.,
+	// for checking the destinations and fees against what was intended).
+	//
+	Approve func(ctx context.Context, desc *DescribeTransferResult) error
+}
+
+// Sync brings the view-only wallet up to date with the outputs spent by the
+// signer: its outputs are imported into the signer, which then hands back
+// their key images.
+//
+// As transactions can only be signed for outputs imported into the signer,
+// this must be done before creating transactions spending outputs received
+// since the last sync.
+//
+func (s *ColdSigner) Sync(ctx context.Context) (*ImportKeyImagesResult, error) {
+	outputs, err := s.ViewOnly.ExportOutputs(ctx, ExportOutputsParams{})
+	if err != nil {
+		return nil, fmt.Errorf("export outputs: %w", err)
+	}
+
+	if _, err := s.Signer.ImportOutputs(ctx, outputs.OutputsDataHex); err != nil {
+		return nil, fmt.Errorf("import outputs: %w", err)
+	}
+
+	keyImages, err := s.Signer.ExportKeyImages(ctx, ExportKeyImagesParams{})
+	if err != nil {
+		return nil, fmt.Errorf("export key images: %w", err)
+	}
+
+	resp, err := s.ViewOnly.ImportKeyImages(ctx, ImportKeyImagesParams{
+		Offset:          keyImages.Offset,
+		SignedKeyImages: keyImages.SignedKeyImages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("import key images: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SignAndSubmit has the signer sign `unsignedTxset` (as in
+// `TransferResult.UnsignedTxset`) once approved, submitting the signed
+// transactions through the view-only wallet.
+//
+func (s *ColdSigner) SignAndSubmit(
+	ctx context.Context, unsignedTxset string,
+) (*SubmitTransferResult, error) {
+	desc, err := s.Signer.DescribeTransfer(ctx, DescribeTransferParams{
+		UnsignedTxset: unsignedTxset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describe transfer: %w", err)
+	}
+
+	if len(desc.Desc) == 0 {
+		return nil, ErrNothingToSign
+	}
+
+	if s.Approve != nil {
+		if err := s.Approve(ctx, desc); err != nil {
+			return nil, fmt.Errorf("approve: %w", err)
+		}
+	}
+
+	signed, err := s.Signer.SignTransfer(ctx, SignTransferParams{
+		UnsignedTxset: unsignedTxset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sign transfer: %w", err)
+	}
+
+	resp, err := s.ViewOnly.SubmitTransfer(ctx, signed.SignedTxset)
+	if err != nil {
+		return nil, fmt.Errorf("submit transfer: %w", err)
+	}
+
+	return resp, nil
+}
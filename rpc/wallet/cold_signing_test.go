@@ -0,0 +1,157 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+// fakeWallet answers each method with a canned result, recording the
+// methods called and the params they were called with.
+type fakeWallet struct {
+	results map[string]string
+	calls   []string
+	params  map[string]map[string]interface{}
+}
+
+func (w *fakeWallet) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	if w.params == nil {
+		w.params = map[string]map[string]interface{}{}
+	}
+
+	w.calls = append(w.calls, method)
+	w.params[method] = fields
+
+	body, ok := w.results[method]
+	if !ok {
+		return errors.New("unexpected method " + method)
+	}
+
+	return json.Unmarshal([]byte(body), result)
+}
+
+func TestColdSigner(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("sync", func(t *testing.T) {
+		viewOnly := &fakeWallet{results: map[string]string{
+			"export_outputs":    `{"outputs_data_hex":"0a0b"}`,
+			"import_key_images": `{"height":100,"spent":10,"unspent":20}`,
+		}}
+		signer := &fakeWallet{results: map[string]string{
+			"import_outputs": `{"num_imported":2}`,
+			"export_key_images": `{"offset":3,"signed_key_images":[
+				{"key_image":"ki","signature":"sig"}]}`,
+		}}
+
+		s := &wallet.ColdSigner{
+			ViewOnly: wallet.NewClient(viewOnly),
+			Signer:   wallet.NewClient(signer),
+		}
+
+		resp, err := s.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(20), resp.Unspent)
+
+		assert.Equal(t, []string{"export_outputs", "import_key_images"},
+			viewOnly.calls)
+		assert.Equal(t, []string{"import_outputs", "export_key_images"},
+			signer.calls)
+
+		assert.Equal(t, "0a0b",
+			signer.params["import_outputs"]["outputs_data_hex"])
+		assert.Equal(t, float64(3),
+			viewOnly.params["import_key_images"]["offset"])
+	})
+
+	t.Run("sign and submit", func(t *testing.T) {
+		viewOnly := &fakeWallet{results: map[string]string{
+			"submit_transfer": `{"tx_hash_list":["hash"]}`,
+		}}
+		signer := &fakeWallet{results: map[string]string{
+			"describe_transfer": `{"desc":[{"amount_out":5,"fee":1,
+				"recipients":[{"address":"addr","amount":4}]}]}`,
+			"sign_transfer": `{"signed_txset":"signed",
+				"tx_hash_list":["hash"]}`,
+		}}
+
+		var approved *wallet.DescribeTransferResult
+
+		s := &wallet.ColdSigner{
+			ViewOnly: wallet.NewClient(viewOnly),
+			Signer:   wallet.NewClient(signer),
+			Approve: func(
+				_ context.Context, desc *wallet.DescribeTransferResult,
+			) error {
+				approved = desc
+				return nil
+			},
+		}
+
+		resp, err := s.SignAndSubmit(ctx, "unsigned")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"hash"}, resp.TxHashList)
+
+		require.NotNil(t, approved)
+		assert.Equal(t, "addr", approved.Desc[0].Recipients[0].Address)
+
+		assert.Equal(t, "unsigned",
+			signer.params["sign_transfer"]["unsigned_txset"])
+		assert.Equal(t, "signed",
+			viewOnly.params["submit_transfer"]["tx_data_hex"])
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		viewOnly := &fakeWallet{}
+		signer := &fakeWallet{results: map[string]string{
+			"describe_transfer": `{"desc":[{"amount_out":5}]}`,
+		}}
+
+		rejection := errors.New("unexpected destination")
+
+		s := &wallet.ColdSigner{
+			ViewOnly: wallet.NewClient(viewOnly),
+			Signer:   wallet.NewClient(signer),
+			Approve: func(context.Context, *wallet.DescribeTransferResult) error {
+				return rejection
+			},
+		}
+
+		_, err := s.SignAndSubmit(ctx, "unsigned")
+		assert.ErrorIs(t, err, rejection)
+		assert.Equal(t, []string{"describe_transfer"}, signer.calls)
+		assert.Empty(t, viewOnly.calls)
+	})
+
+	t.Run("nothing to sign", func(t *testing.T) {
+		signer := &fakeWallet{results: map[string]string{
+			"describe_transfer": `{"desc":[]}`,
+		}}
+
+		s := &wallet.ColdSigner{
+			ViewOnly: wallet.NewClient(&fakeWallet{}),
+			Signer:   wallet.NewClient(signer),
+		}
+
+		_, err := s.SignAndSubmit(ctx, "unsigned")
+		assert.ErrorIs(t, err, wallet.ErrNothingToSign)
+	})
+}
@@ -0,0 +1,308 @@
+package wallet
+
+import (
+	"github.com/duggavo/go-monero/rpc"
+)
+
+// Error codes that `monero-wallet-rpc` fills JSONRPC error objects with (see
+// `wallet_rpc_server_error_codes.h`).
+const (
+	ErrorCodeUnknownError            = -1
+	ErrorCodeWrongAddress            = -2
+	ErrorCodeDaemonIsBusy            = -3
+	ErrorCodeGenericTransferError    = -4
+	ErrorCodeWrongPaymentID          = -5
+	ErrorCodeTransferType            = -6
+	ErrorCodeDenied                  = -7
+	ErrorCodeWrongTxID               = -8
+	ErrorCodeWrongSignature          = -9
+	ErrorCodeWrongKeyImage           = -10
+	ErrorCodeWrongURI                = -11
+	ErrorCodeWrongIndex              = -12
+	ErrorCodeNotOpen                 = -13
+	ErrorCodeAccountIndexOutOfBounds = -14
+	ErrorCodeAddressIndexOutOfBounds = -15
+	ErrorCodeTxNotPossible           = -16
+	ErrorCodeNotEnoughMoney          = -17
+	ErrorCodeTxTooLarge              = -18
+	ErrorCodeNotEnoughOutsToMix      = -19
+	ErrorCodeZeroDestination         = -20
+	ErrorCodeWalletAlreadyExists     = -21
+	ErrorCodeInvalidPassword         = -22
+	ErrorCodeNoWalletDir             = -23
+	ErrorCodeNoTxKey                 = -24
+	ErrorCodeWrongKey                = -25
+	ErrorCodeBadHex                  = -26
+	ErrorCodeBadTxMetadata           = -27
+	ErrorCodeAlreadyMultisig         = -28
+	ErrorCodeWatchOnly               = -29
+	ErrorCodeBadMultisigInfo         = -30
+	ErrorCodeNotMultisig             = -31
+	ErrorCodeWrongLR                 = -32
+	ErrorCodeThresholdNotReached     = -33
+	ErrorCodeBadMultisigTxData       = -34
+	ErrorCodeMultisigSignature       = -35
+	ErrorCodeMultisigSubmission      = -36
+	ErrorCodeNotEnoughUnlockedMoney  = -37
+	ErrorCodeNoDaemonConnection      = -38
+	ErrorCodeBadUnsignedTxData       = -39
+	ErrorCodeBadSignedTxData         = -40
+	ErrorCodeSignedSubmission        = -41
+	ErrorCodeSignUnsigned            = -42
+	ErrorCodeNonDeterministic        = -43
+	ErrorCodeInvalidLogLevel         = -44
+	ErrorCodeAttributeNotFound       = -45
+	ErrorCodeZeroAmount              = -46
+	ErrorCodeInvalidSignatureType    = -47
+	ErrorCodeDisabled                = -48
+)
+
+// Sentinels matching the errors that `monero-wallet-rpc` replies with, i.e.,
+// for use with `errors.Is`, which reports true for any `*rpc.RPCError` with
+// the same code.
+var (
+	// ErrUnknownError matches `ErrorCodeUnknownError`.
+	ErrUnknownError = &rpc.RPCError{
+		Code: ErrorCodeUnknownError, Message: "unknown error",
+	}
+
+	// ErrWrongAddress matches `ErrorCodeWrongAddress`.
+	ErrWrongAddress = &rpc.RPCError{
+		Code: ErrorCodeWrongAddress, Message: "wrong address",
+	}
+
+	// ErrDaemonIsBusy matches `ErrorCodeDaemonIsBusy`.
+	ErrDaemonIsBusy = &rpc.RPCError{
+		Code: ErrorCodeDaemonIsBusy, Message: "daemon is busy",
+	}
+
+	// ErrGenericTransferError matches `ErrorCodeGenericTransferError`.
+	ErrGenericTransferError = &rpc.RPCError{
+		Code: ErrorCodeGenericTransferError, Message: "generic transfer error",
+	}
+
+	// ErrWrongPaymentID matches `ErrorCodeWrongPaymentID`.
+	ErrWrongPaymentID = &rpc.RPCError{
+		Code: ErrorCodeWrongPaymentID, Message: "wrong payment id",
+	}
+
+	// ErrTransferType matches `ErrorCodeTransferType`.
+	ErrTransferType = &rpc.RPCError{
+		Code: ErrorCodeTransferType, Message: "transfer type",
+	}
+
+	// ErrDenied matches `ErrorCodeDenied`.
+	ErrDenied = &rpc.RPCError{
+		Code: ErrorCodeDenied, Message: "denied",
+	}
+
+	// ErrWrongTxID matches `ErrorCodeWrongTxID`.
+	ErrWrongTxID = &rpc.RPCError{
+		Code: ErrorCodeWrongTxID, Message: "wrong txid",
+	}
+
+	// ErrWrongSignature matches `ErrorCodeWrongSignature`.
+	ErrWrongSignature = &rpc.RPCError{
+		Code: ErrorCodeWrongSignature, Message: "wrong signature",
+	}
+
+	// ErrWrongKeyImage matches `ErrorCodeWrongKeyImage`.
+	ErrWrongKeyImage = &rpc.RPCError{
+		Code: ErrorCodeWrongKeyImage, Message: "wrong key image",
+	}
+
+	// ErrWrongURI matches `ErrorCodeWrongURI`.
+	ErrWrongURI = &rpc.RPCError{
+		Code: ErrorCodeWrongURI, Message: "wrong uri",
+	}
+
+	// ErrWrongIndex matches `ErrorCodeWrongIndex`.
+	ErrWrongIndex = &rpc.RPCError{
+		Code: ErrorCodeWrongIndex, Message: "wrong index",
+	}
+
+	// ErrNotOpen matches `ErrorCodeNotOpen`.
+	ErrNotOpen = &rpc.RPCError{
+		Code: ErrorCodeNotOpen, Message: "no wallet file",
+	}
+
+	// ErrAccountIndexOutOfBounds matches `ErrorCodeAccountIndexOutOfBounds`.
+	ErrAccountIndexOutOfBounds = &rpc.RPCError{
+		Code: ErrorCodeAccountIndexOutOfBounds, Message: "account index out of bounds",
+	}
+
+	// ErrAddressIndexOutOfBounds matches `ErrorCodeAddressIndexOutOfBounds`.
+	ErrAddressIndexOutOfBounds = &rpc.RPCError{
+		Code: ErrorCodeAddressIndexOutOfBounds, Message: "address index out of bounds",
+	}
+
+	// ErrTxNotPossible matches `ErrorCodeTxNotPossible`.
+	ErrTxNotPossible = &rpc.RPCError{
+		Code: ErrorCodeTxNotPossible, Message: "tx not possible",
+	}
+
+	// ErrNotEnoughMoney matches `ErrorCodeNotEnoughMoney`.
+	ErrNotEnoughMoney = &rpc.RPCError{
+		Code: ErrorCodeNotEnoughMoney, Message: "not enough money",
+	}
+
+	// ErrTxTooLarge matches `ErrorCodeTxTooLarge`.
+	ErrTxTooLarge = &rpc.RPCError{
+		Code: ErrorCodeTxTooLarge, Message: "tx too large",
+	}
+
+	// ErrNotEnoughOutsToMix matches `ErrorCodeNotEnoughOutsToMix`.
+	ErrNotEnoughOutsToMix = &rpc.RPCError{
+		Code: ErrorCodeNotEnoughOutsToMix, Message: "not enough outs to mix",
+	}
+
+	// ErrZeroDestination matches `ErrorCodeZeroDestination`.
+	ErrZeroDestination = &rpc.RPCError{
+		Code: ErrorCodeZeroDestination, Message: "zero destination",
+	}
+
+	// ErrWalletAlreadyExists matches `ErrorCodeWalletAlreadyExists`.
+	ErrWalletAlreadyExists = &rpc.RPCError{
+		Code: ErrorCodeWalletAlreadyExists, Message: "wallet already exists",
+	}
+
+	// ErrInvalidPassword matches `ErrorCodeInvalidPassword`.
+	ErrInvalidPassword = &rpc.RPCError{
+		Code: ErrorCodeInvalidPassword, Message: "invalid password",
+	}
+
+	// ErrNoWalletDir matches `ErrorCodeNoWalletDir`.
+	ErrNoWalletDir = &rpc.RPCError{
+		Code: ErrorCodeNoWalletDir, Message: "no wallet dir",
+	}
+
+	// ErrNoTxKey matches `ErrorCodeNoTxKey`.
+	ErrNoTxKey = &rpc.RPCError{
+		Code: ErrorCodeNoTxKey, Message: "no tx key",
+	}
+
+	// ErrWrongKey matches `ErrorCodeWrongKey`.
+	ErrWrongKey = &rpc.RPCError{
+		Code: ErrorCodeWrongKey, Message: "wrong key",
+	}
+
+	// ErrBadHex matches `ErrorCodeBadHex`.
+	ErrBadHex = &rpc.RPCError{
+		Code: ErrorCodeBadHex, Message: "bad hex",
+	}
+
+	// ErrBadTxMetadata matches `ErrorCodeBadTxMetadata`.
+	ErrBadTxMetadata = &rpc.RPCError{
+		Code: ErrorCodeBadTxMetadata, Message: "bad tx metadata",
+	}
+
+	// ErrAlreadyMultisig matches `ErrorCodeAlreadyMultisig`.
+	ErrAlreadyMultisig = &rpc.RPCError{
+		Code: ErrorCodeAlreadyMultisig, Message: "already multisig",
+	}
+
+	// ErrWatchOnly matches `ErrorCodeWatchOnly`.
+	ErrWatchOnly = &rpc.RPCError{
+		Code: ErrorCodeWatchOnly, Message: "watch only",
+	}
+
+	// ErrBadMultisigInfo matches `ErrorCodeBadMultisigInfo`.
+	ErrBadMultisigInfo = &rpc.RPCError{
+		Code: ErrorCodeBadMultisigInfo, Message: "bad multisig info",
+	}
+
+	// ErrNotMultisig matches `ErrorCodeNotMultisig`.
+	ErrNotMultisig = &rpc.RPCError{
+		Code: ErrorCodeNotMultisig, Message: "not multisig",
+	}
+
+	// ErrWrongLR matches `ErrorCodeWrongLR`.
+	ErrWrongLR = &rpc.RPCError{
+		Code: ErrorCodeWrongLR, Message: "wrong lr",
+	}
+
+	// ErrThresholdNotReached matches `ErrorCodeThresholdNotReached`.
+	ErrThresholdNotReached = &rpc.RPCError{
+		Code: ErrorCodeThresholdNotReached, Message: "threshold not reached",
+	}
+
+	// ErrBadMultisigTxData matches `ErrorCodeBadMultisigTxData`.
+	ErrBadMultisigTxData = &rpc.RPCError{
+		Code: ErrorCodeBadMultisigTxData, Message: "bad multisig tx data",
+	}
+
+	// ErrMultisigSignature matches `ErrorCodeMultisigSignature`.
+	ErrMultisigSignature = &rpc.RPCError{
+		Code: ErrorCodeMultisigSignature, Message: "multisig signature",
+	}
+
+	// ErrMultisigSubmission matches `ErrorCodeMultisigSubmission`.
+	ErrMultisigSubmission = &rpc.RPCError{
+		Code: ErrorCodeMultisigSubmission, Message: "multisig submission",
+	}
+
+	// ErrNotEnoughUnlockedMoney matches `ErrorCodeNotEnoughUnlockedMoney`.
+	ErrNotEnoughUnlockedMoney = &rpc.RPCError{
+		Code: ErrorCodeNotEnoughUnlockedMoney, Message: "not enough unlocked money",
+	}
+
+	// ErrNoDaemonConnection matches `ErrorCodeNoDaemonConnection`.
+	ErrNoDaemonConnection = &rpc.RPCError{
+		Code: ErrorCodeNoDaemonConnection, Message: "no daemon connection",
+	}
+
+	// ErrBadUnsignedTxData matches `ErrorCodeBadUnsignedTxData`.
+	ErrBadUnsignedTxData = &rpc.RPCError{
+		Code: ErrorCodeBadUnsignedTxData, Message: "bad unsigned tx data",
+	}
+
+	// ErrBadSignedTxData matches `ErrorCodeBadSignedTxData`.
+	ErrBadSignedTxData = &rpc.RPCError{
+		Code: ErrorCodeBadSignedTxData, Message: "bad signed tx data",
+	}
+
+	// ErrSignedSubmission matches `ErrorCodeSignedSubmission`.
+	ErrSignedSubmission = &rpc.RPCError{
+		Code: ErrorCodeSignedSubmission, Message: "signed submission",
+	}
+
+	// ErrSignUnsigned matches `ErrorCodeSignUnsigned`.
+	ErrSignUnsigned = &rpc.RPCError{
+		Code: ErrorCodeSignUnsigned, Message: "sign unsigned",
+	}
+
+	// ErrNonDeterministic matches `ErrorCodeNonDeterministic`.
+	ErrNonDeterministic = &rpc.RPCError{
+		Code: ErrorCodeNonDeterministic, Message: "non deterministic",
+	}
+
+	// ErrInvalidLogLevel matches `ErrorCodeInvalidLogLevel`.
+	ErrInvalidLogLevel = &rpc.RPCError{
+		Code: ErrorCodeInvalidLogLevel, Message: "invalid log level",
+	}
+
+	// ErrAttributeNotFound matches `ErrorCodeAttributeNotFound`.
+	ErrAttributeNotFound = &rpc.RPCError{
+		Code: ErrorCodeAttributeNotFound, Message: "attribute not found",
+	}
+
+	// ErrZeroAmount matches `ErrorCodeZeroAmount`.
+	ErrZeroAmount = &rpc.RPCError{
+		Code: ErrorCodeZeroAmount, Message: "zero amount",
+	}
+
+	// ErrInvalidSignatureType matches `ErrorCodeInvalidSignatureType`.
+	ErrInvalidSignatureType = &rpc.RPCError{
+		Code: ErrorCodeInvalidSignatureType, Message: "invalid signature type",
+	}
+
+	// ErrDisabled matches `ErrorCodeDisabled`.
+	ErrDisabled = &rpc.RPCError{
+		Code: ErrorCodeDisabled, Message: "disabled",
+	}
+
+	// ErrBusy is an alias of ErrDaemonIsBusy, matching errors from a
+	// wallet whose daemon is too busy to serve the request.
+	//
+	ErrBusy = ErrDaemonIsBusy
+)
@@ -227,3 +227,308 @@ func (c *Client) GetTransfers(ctx context.Context, params GetTransfersParams) (*
 
 	return resp, nil
 }
+
+// DescribeTransfer returns a set of unsigned (or multisig) transactions in a
+// human-readable form, letting the signer review them before signing.
+func (c *Client) DescribeTransfer(ctx context.Context, params DescribeTransferParams) (*DescribeTransferResult, error) {
+	resp := &DescribeTransferResult{}
+
+	if err := c.JSONRPC(ctx, "describe_transfer", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SignTransfer signs a transaction set created by a view-only wallet.
+func (c *Client) SignTransfer(ctx context.Context, params SignTransferParams) (*SignTransferResult, error) {
+	resp := &SignTransferResult{}
+
+	if err := c.JSONRPC(ctx, "sign_transfer", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SubmitTransfer submits a transaction set signed by SignTransfer.
+func (c *Client) SubmitTransfer(ctx context.Context, txDataHex string) (*SubmitTransferResult, error) {
+	resp := &SubmitTransferResult{}
+
+	if err := c.JSONRPC(ctx, "submit_transfer", map[string]string{
+		"tx_data_hex": txDataHex,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExportOutputs exports the outputs of the wallet, for importing them in a
+// wallet able to compute their key images (see ImportOutputs).
+func (c *Client) ExportOutputs(ctx context.Context, params ExportOutputsParams) (*ExportOutputsResult, error) {
+	resp := &ExportOutputsResult{}
+
+	if err := c.JSONRPC(ctx, "export_outputs", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ImportOutputs imports outputs exported by another wallet.
+func (c *Client) ImportOutputs(ctx context.Context, outputsDataHex string) (*ImportOutputsResult, error) {
+	resp := &ImportOutputsResult{}
+
+	if err := c.JSONRPC(ctx, "import_outputs", map[string]string{
+		"outputs_data_hex": outputsDataHex,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExportKeyImages exports signed key images, for a view-only wallet to find
+// out which of its outputs have been spent (see ImportKeyImages).
+func (c *Client) ExportKeyImages(ctx context.Context, params ExportKeyImagesParams) (*ExportKeyImagesResult, error) {
+	resp := &ExportKeyImagesResult{}
+
+	if err := c.JSONRPC(ctx, "export_key_images", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ImportKeyImages imports signed key images exported by another wallet.
+func (c *Client) ImportKeyImages(ctx context.Context, params ImportKeyImagesParams) (*ImportKeyImagesResult, error) {
+	resp := &ImportKeyImagesResult{}
+
+	if err := c.JSONRPC(ctx, "import_key_images", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PrepareMultisig prepares the wallet for becoming multisig, giving the info
+// to share with the other participants.
+func (c *Client) PrepareMultisig(ctx context.Context) (*PrepareMultisigResult, error) {
+	resp := &PrepareMultisigResult{}
+
+	if err := c.JSONRPC(ctx, "prepare_multisig", nil, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// MakeMultisig turns the wallet into a multisig one out of the info given by
+// PrepareMultisig to the other participants.
+func (c *Client) MakeMultisig(ctx context.Context, params MakeMultisigParams) (*MakeMultisigResult, error) {
+	resp := &MakeMultisigResult{}
+
+	if err := c.JSONRPC(ctx, "make_multisig", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExchangeMultisigKeys performs a round of key exchange, to be repeated with
+// the info given by the previous round until the wallet is ready (see
+// IsMultisig).
+func (c *Client) ExchangeMultisigKeys(ctx context.Context, params ExchangeMultisigKeysParams) (*ExchangeMultisigKeysResult, error) {
+	resp := &ExchangeMultisigKeysResult{}
+
+	if err := c.JSONRPC(ctx, "exchange_multisig_keys", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FinalizeMultisig completes the setup of an N-1/N multisig wallet.
+//
+// Only supported by versions of `monero-wallet-rpc` prior to v0.18, later
+// ones completing the setup through ExchangeMultisigKeys instead.
+func (c *Client) FinalizeMultisig(ctx context.Context, params FinalizeMultisigParams) (*FinalizeMultisigResult, error) {
+	resp := &FinalizeMultisigResult{}
+
+	if err := c.JSONRPC(ctx, "finalize_multisig", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ExportMultisigInfo exports the info other participants need for signing
+// transactions spending the outputs received by the wallet.
+func (c *Client) ExportMultisigInfo(ctx context.Context) (*ExportMultisigInfoResult, error) {
+	resp := &ExportMultisigInfoResult{}
+
+	if err := c.JSONRPC(ctx, "export_multisig_info", nil, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// ImportMultisigInfo imports the info exported by the other participants.
+func (c *Client) ImportMultisigInfo(ctx context.Context, info []string) (*ImportMultisigInfoResult, error) {
+	resp := &ImportMultisigInfoResult{}
+
+	if err := c.JSONRPC(ctx, "import_multisig_info", map[string][]string{
+		"info": info,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SignMultisig adds the wallet's signature to a multisig transaction set.
+func (c *Client) SignMultisig(ctx context.Context, txDataHex string) (*SignMultisigResult, error) {
+	resp := &SignMultisigResult{}
+
+	if err := c.JSONRPC(ctx, "sign_multisig", map[string]string{
+		"tx_data_hex": txDataHex,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SubmitMultisig submits a multisig transaction set signed by enough
+// participants.
+func (c *Client) SubmitMultisig(ctx context.Context, txDataHex string) (*SubmitMultisigResult, error) {
+	resp := &SubmitMultisigResult{}
+
+	if err := c.JSONRPC(ctx, "submit_multisig", map[string]string{
+		"tx_data_hex": txDataHex,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTxKey gets the secret key of an outgoing transaction, letting its
+// recipients verify the payment with CheckTxKey.
+func (c *Client) GetTxKey(ctx context.Context, txid string) (*GetTxKeyResult, error) {
+	resp := &GetTxKeyResult{}
+
+	if err := c.JSONRPC(ctx, "get_tx_key", map[string]string{
+		"txid": txid,
+	}, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CheckTxKey checks the amount a transaction sent to an address given its
+// secret key.
+func (c *Client) CheckTxKey(ctx context.Context, params CheckTxKeyParams) (*CheckTxKeyResult, error) {
+	resp := &CheckTxKeyResult{}
+
+	if err := c.JSONRPC(ctx, "check_tx_key", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTxProof generates a signature proving that a transaction sent funds to
+// an address (or, if incoming, that the wallet received them).
+func (c *Client) GetTxProof(ctx context.Context, params GetTxProofParams) (*SignatureResult, error) {
+	resp := &SignatureResult{}
+
+	if err := c.JSONRPC(ctx, "get_tx_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CheckTxProof checks a signature generated by GetTxProof.
+func (c *Client) CheckTxProof(ctx context.Context, params CheckTxProofParams) (*CheckTxProofResult, error) {
+	resp := &CheckTxProofResult{}
+
+	if err := c.JSONRPC(ctx, "check_tx_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetSpendProof generates a signature proving that the wallet spent the
+// inputs of a transaction.
+func (c *Client) GetSpendProof(ctx context.Context, params GetSpendProofParams) (*SignatureResult, error) {
+	resp := &SignatureResult{}
+
+	if err := c.JSONRPC(ctx, "get_spend_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CheckSpendProof checks a signature generated by GetSpendProof.
+func (c *Client) CheckSpendProof(ctx context.Context, params CheckSpendProofParams) (*CheckSpendProofResult, error) {
+	resp := &CheckSpendProofResult{}
+
+	if err := c.JSONRPC(ctx, "check_spend_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetReserveProof generates a signature proving that the wallet (or one of
+// its accounts) holds an amount in unspent outputs.
+func (c *Client) GetReserveProof(ctx context.Context, params GetReserveProofParams) (*SignatureResult, error) {
+	resp := &SignatureResult{}
+
+	if err := c.JSONRPC(ctx, "get_reserve_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CheckReserveProof checks a signature generated by GetReserveProof.
+func (c *Client) CheckReserveProof(ctx context.Context, params CheckReserveProofParams) (*CheckReserveProofResult, error) {
+	resp := &CheckReserveProofResult{}
+
+	if err := c.JSONRPC(ctx, "check_reserve_proof", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Sign signs a string with the keys of an address of the wallet (the primary
+// one by default).
+func (c *Client) Sign(ctx context.Context, params SignParams) (*SignatureResult, error) {
+	resp := &SignatureResult{}
+
+	if err := c.JSONRPC(ctx, "sign", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Verify verifies a signature on a string, as generated by Sign.
+func (c *Client) Verify(ctx context.Context, params VerifyParams) (*VerifyResult, error) {
+	resp := &VerifyResult{}
+
+	if err := c.JSONRPC(ctx, "verify", params, resp); err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
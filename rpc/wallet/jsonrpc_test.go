@@ -0,0 +1,210 @@
+package wallet_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+func TestProofs(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		method   string
+		reply    string
+		call     func(c *wallet.Client) (interface{}, error)
+		params   map[string]interface{}
+		expected interface{}
+	}{
+		{
+			method: "get_tx_key",
+			reply:  `{"tx_key":"feba662cf8fb6d0d"}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.GetTxKey(ctx, "txid")
+			},
+			params:   map[string]interface{}{"txid": "txid"},
+			expected: &wallet.GetTxKeyResult{TxKey: "feba662cf8fb6d0d"},
+		},
+		{
+			method: "check_tx_key",
+			reply: `{"confirmations":0,"in_pool":true,
+				"received":1000000000000}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.CheckTxKey(ctx, wallet.CheckTxKeyParams{
+					TxID:    "txid",
+					TxKey:   "feba662cf8fb6d0d",
+					Address: "address",
+				})
+			},
+			params: map[string]interface{}{
+				"txid":    "txid",
+				"tx_key":  "feba662cf8fb6d0d",
+				"address": "address",
+			},
+			expected: &wallet.CheckTxKeyResult{
+				InPool:   true,
+				Received: 1000000000000,
+			},
+		},
+		{
+			method: "get_tx_proof",
+			reply:  `{"signature":"OutProofV2..."}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.GetTxProof(ctx, wallet.GetTxProofParams{
+					TxID:    "txid",
+					Address: "address",
+					Message: "invoice 42",
+				})
+			},
+			params: map[string]interface{}{
+				"txid":    "txid",
+				"address": "address",
+				"message": "invoice 42",
+			},
+			expected: &wallet.SignatureResult{Signature: "OutProofV2..."},
+		},
+		{
+			method: "check_tx_proof",
+			reply: `{"confirmations":12,"good":true,
+				"in_pool":false,"received":1000}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.CheckTxProof(ctx, wallet.CheckTxProofParams{
+					TxID:      "txid",
+					Address:   "address",
+					Signature: "OutProofV2...",
+				})
+			},
+			params: map[string]interface{}{
+				"txid":      "txid",
+				"address":   "address",
+				"signature": "OutProofV2...",
+			},
+			expected: &wallet.CheckTxProofResult{
+				Confirmations: 12,
+				Good:          true,
+				Received:      1000,
+			},
+		},
+		{
+			method: "get_spend_proof",
+			reply:  `{"signature":"SpendProofV1..."}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.GetSpendProof(ctx, wallet.GetSpendProofParams{
+					TxID: "txid",
+				})
+			},
+			params:   map[string]interface{}{"txid": "txid"},
+			expected: &wallet.SignatureResult{Signature: "SpendProofV1..."},
+		},
+		{
+			method: "check_spend_proof",
+			reply:  `{"good":true}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.CheckSpendProof(ctx, wallet.CheckSpendProofParams{
+					TxID:      "txid",
+					Message:   "refund",
+					Signature: "SpendProofV1...",
+				})
+			},
+			params: map[string]interface{}{
+				"txid":      "txid",
+				"message":   "refund",
+				"signature": "SpendProofV1...",
+			},
+			expected: &wallet.CheckSpendProofResult{Good: true},
+		},
+		{
+			method: "get_reserve_proof",
+			reply:  `{"signature":"ReserveProofV2..."}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.GetReserveProof(ctx, wallet.GetReserveProofParams{
+					AccountIndex: 1,
+					Amount:       5000,
+					Message:      "audit",
+				})
+			},
+			params: map[string]interface{}{
+				"all":           false,
+				"account_index": float64(1),
+				"amount":        float64(5000),
+				"message":       "audit",
+			},
+			expected: &wallet.SignatureResult{Signature: "ReserveProofV2..."},
+		},
+		{
+			method: "check_reserve_proof",
+			reply:  `{"good":true,"spent":1000,"total":5000}`,
+			call: func(c *wallet.Client) (interface{}, error) {
+				return c.CheckReserveProof(ctx, wallet.CheckReserveProofParams{
+					Address:   "address",
+					Message:   "audit",
+					Signature: "ReserveProofV2...",
+				})
+			},
+			params: map[string]interface{}{
+				"address":   "address",
+				"message":   "audit",
+				"signature": "ReserveProofV2...",
+			},
+			expected: &wallet.CheckReserveProofResult{
+				Good:  true,
+				Spent: 1000,
+				Total: 5000,
+			},
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.method, func(t *testing.T) {
+			fake := &fakeWallet{results: map[string]string{
+				tc.method: tc.reply,
+			}}
+
+			result, err := tc.call(wallet.NewClient(fake))
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{tc.method}, fake.calls)
+			assert.Equal(t, tc.params, fake.params[tc.method])
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	ctx := context.Background()
+
+	fake := &fakeWallet{results: map[string]string{
+		"sign": `{"signature":"SigV2..."}`,
+		"verify": `{"good":true,"version":2,"old":false,
+			"signature_type":"view"}`,
+	}}
+	client := wallet.NewClient(fake)
+
+	signed, err := client.Sign(ctx, wallet.SignParams{
+		Data:          "withdraw to 4...",
+		AccountIndex:  1,
+		AddressIndex:  2,
+		SignatureType: wallet.SignatureView,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"data":           "withdraw to 4...",
+		"account_index":  float64(1),
+		"address_index":  float64(2),
+		"signature_type": "view",
+	}, fake.params["sign"])
+
+	verified, err := client.Verify(ctx, wallet.VerifyParams{
+		Data:      "withdraw to 4...",
+		Address:   "4...",
+		Signature: signed.Signature,
+	})
+	require.NoError(t, err)
+	assert.True(t, verified.Good)
+	assert.Equal(t, wallet.SignatureView, verified.SignatureType)
+	assert.Equal(t, "SigV2...", fake.params["verify"]["signature"])
+}
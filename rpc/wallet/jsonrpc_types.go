@@ -263,3 +263,248 @@ type GetTransfersResult struct {
 	Failed  []TransferInfo
 	Pool    []TransferInfo
 }
+
+type DescribeTransferParams struct {
+	UnsignedTxset string `json:"unsigned_txset,omitempty"` // Set of unsigned tx returned by "transfer" or "transfer_split" methods.
+	MultisigTxset string `json:"multisig_txset,omitempty"` // Set of unsigned multisig txes returned by "transfer" or "transfer_split" methods.
+}
+
+type TransferDescription struct {
+	AmountIn      uint64        `json:"amount_in"`      // Amount in, in atomic units.
+	AmountOut     uint64        `json:"amount_out"`     // Amount out, in atomic units.
+	Recipients    []Destination `json:"recipients"`     // Destinations of the transfer.
+	ChangeAmount  uint64        `json:"change_amount"`  // Amount sent back as change, in atomic units.
+	ChangeAddress string        `json:"change_address"` // Address the change is sent to.
+	Fee           uint64        `json:"fee"`            // Fee paid, in atomic units.
+	RingSize      uint          `json:"ring_size"`      // Ring size of the inputs.
+	UnlockTime    uint64        `json:"unlock_time"`    // Number of blocks before the outputs can be spent.
+	DummyOutputs  uint          `json:"dummy_outputs"`  // Number of dummy outputs.
+	Extra         string        `json:"extra"`          // Extra field of the transaction, as hex.
+	PaymentId     string        `json:"payment_id"`
+}
+
+type DescribeTransferResult struct {
+	Desc    []TransferDescription `json:"desc"` // Description of each transaction of the set.
+	Summary struct {
+		AmountIn      uint64        `json:"amount_in"`
+		AmountOut     uint64        `json:"amount_out"`
+		Recipients    []Destination `json:"recipients"`
+		ChangeAmount  uint64        `json:"change_amount"`
+		ChangeAddress string        `json:"change_address"`
+		Fee           uint64        `json:"fee"`
+	} `json:"summary"` // Totals over all the transactions of the set.
+}
+
+type SignTransferParams struct {
+	UnsignedTxset string `json:"unsigned_txset"`        // Set of unsigned tx returned by "transfer" or "transfer_split" methods.
+	ExportRaw     bool   `json:"export_raw,omitempty"`  // If true, return the raw transaction data.
+	GetTxKeys     bool   `json:"get_tx_keys,omitempty"` // Return the transaction keys after signing.
+}
+
+type SignTransferResult struct {
+	SignedTxset string   `json:"signed_txset"` // Set of signed tx to be used for submitting transfer.
+	TxHashList  []string `json:"tx_hash_list"`
+	TxRawList   []string `json:"tx_raw_list"` // Raw transactions, if requested with ExportRaw.
+	TxKeyList   []string `json:"tx_key_list"`
+}
+
+type SubmitTransferResult struct {
+	TxHashList []string `json:"tx_hash_list"`
+}
+
+type ExportOutputsParams struct {
+	All   bool   `json:"all,omitempty"`   // Export all outputs rather than only those not exported yet.
+	Start uint64 `json:"start,omitempty"` // Index of the first output to export.
+	Count uint64 `json:"count,omitempty"` // Number of outputs to export (all from Start if 0).
+}
+
+type ExportOutputsResult struct {
+	OutputsDataHex string `json:"outputs_data_hex"` // Wallet outputs in hex format.
+}
+
+type ImportOutputsResult struct {
+	NumImported uint64 `json:"num_imported"` // Number of outputs imported.
+}
+
+type SignedKeyImage struct {
+	KeyImage  string `json:"key_image"`
+	Signature string `json:"signature"`
+}
+
+type ExportKeyImagesParams struct {
+	All bool `json:"all,omitempty"` // Export all key images rather than only those not exported yet.
+}
+
+type ExportKeyImagesResult struct {
+	Offset          uint64           `json:"offset"` // Index of the output that the first key image corresponds to.
+	SignedKeyImages []SignedKeyImage `json:"signed_key_images"`
+}
+
+type ImportKeyImagesParams struct {
+	Offset          uint64           `json:"offset,omitempty"` // Index of the output that the first key image corresponds to.
+	SignedKeyImages []SignedKeyImage `json:"signed_key_images"`
+}
+
+type ImportKeyImagesResult struct {
+	Height  uint64 `json:"height"`
+	Spent   uint64 `json:"spent"`   // Amount spent from key images, in atomic units.
+	Unspent uint64 `json:"unspent"` // Amount still available from key images, in atomic units.
+}
+
+type PrepareMultisigResult struct {
+	MultisigInfo string `json:"multisig_info"` // Multisig string to share with peers to create the multisig wallet.
+}
+
+type MakeMultisigParams struct {
+	MultisigInfo []string `json:"multisig_info"` // List of multisig strings from peers.
+	Threshold    uint     `json:"threshold"`     // Amount of signatures needed to sign a transfer.
+	Password     string   `json:"password"`      // Wallet password.
+}
+
+type MakeMultisigResult struct {
+	Address      string `json:"address"`       // Multisig wallet address.
+	MultisigInfo string `json:"multisig_info"` // Multisig string to share with peers for the next round of key exchange.
+}
+
+type ExchangeMultisigKeysParams struct {
+	MultisigInfo              []string `json:"multisig_info"` // List of multisig strings from peers, from the previous round.
+	Password                  string   `json:"password"`      // Wallet password.
+	ForceUpdateUseWithCaution bool     `json:"force_update_use_with_caution,omitempty"`
+}
+
+type ExchangeMultisigKeysResult struct {
+	Address      string `json:"address"`       // Multisig wallet address.
+	MultisigInfo string `json:"multisig_info"` // Multisig string to share with peers for the next round, if any.
+}
+
+type FinalizeMultisigParams struct {
+	MultisigInfo []string `json:"multisig_info"` // List of multisig strings from peers.
+	Password     string   `json:"password"`      // Wallet password.
+}
+
+type FinalizeMultisigResult struct {
+	Address string `json:"address"` // Multisig wallet address.
+}
+
+type ExportMultisigInfoResult struct {
+	Info string `json:"info"` // Multisig info in hex format for other participants.
+}
+
+type ImportMultisigInfoResult struct {
+	NOutputs uint64 `json:"n_outputs"` // Number of outputs signed with those multisig info.
+}
+
+type SignMultisigResult struct {
+	TxDataHex  string   `json:"tx_data_hex"`  // Multisig transaction in hex format.
+	TxHashList []string `json:"tx_hash_list"` // List of transaction hashes, set once fully signed.
+}
+
+type SubmitMultisigResult struct {
+	TxHashList []string `json:"tx_hash_list"`
+}
+
+type GetTxKeyResult struct {
+	TxKey string `json:"tx_key"` // Transaction secret key.
+}
+
+type CheckTxKeyParams struct {
+	TxID    string `json:"txid"`    // Transaction id.
+	TxKey   string `json:"tx_key"`  // Transaction secret key.
+	Address string `json:"address"` // Destination public address of the transaction.
+}
+
+type CheckTxKeyResult struct {
+	Confirmations uint64 `json:"confirmations"` // Number of block mined after the one with the transaction.
+	InPool        bool   `json:"in_pool"`       // States if the transaction is still in pool or has been added to a block.
+	Received      uint64 `json:"received"`      // Amount of the transaction, in atomic units.
+}
+
+type GetTxProofParams struct {
+	TxID    string `json:"txid"`              // Transaction id.
+	Address string `json:"address"`           // Destination public address of the transaction.
+	Message string `json:"message,omitempty"` // Message to be signed along with the proof.
+}
+
+type CheckTxProofParams struct {
+	TxID      string `json:"txid"`              // Transaction id.
+	Address   string `json:"address"`           // Destination public address of the transaction.
+	Message   string `json:"message,omitempty"` // Message signed along with the proof.
+	Signature string `json:"signature"`         // Transaction signature to confirm.
+}
+
+type CheckTxProofResult struct {
+	Confirmations uint64 `json:"confirmations"` // Number of block mined after the one with the transaction.
+	Good          bool   `json:"good"`          // States if the inputs proves the transaction.
+	InPool        bool   `json:"in_pool"`       // States if the transaction is still in pool or has been added to a block.
+	Received      uint64 `json:"received"`      // Amount of the transaction, in atomic units.
+}
+
+type GetSpendProofParams struct {
+	TxID    string `json:"txid"`              // Transaction id.
+	Message string `json:"message,omitempty"` // Message to be signed along with the proof.
+}
+
+type CheckSpendProofParams struct {
+	TxID      string `json:"txid"`              // Transaction id.
+	Message   string `json:"message,omitempty"` // Message signed along with the proof.
+	Signature string `json:"signature"`         // Spend signature to confirm.
+}
+
+type CheckSpendProofResult struct {
+	Good bool `json:"good"` // States if the inputs proves the spend.
+}
+
+type GetReserveProofParams struct {
+	All          bool   `json:"all"`                     // Proves all the balance of the wallet rather than that of an account.
+	AccountIndex uint   `json:"account_index,omitempty"` // Account to prove the reserve of, unless All is set.
+	Amount       uint64 `json:"amount,omitempty"`        // Amount (in atomic units) to prove the account has in reserve, unless All is set.
+	Message      string `json:"message,omitempty"`       // Message to be signed along with the proof.
+}
+
+type CheckReserveProofParams struct {
+	Address   string `json:"address"`           // Public address of the wallet.
+	Message   string `json:"message,omitempty"` // Message signed along with the proof.
+	Signature string `json:"signature"`         // Reserve signature to confirm.
+}
+
+type CheckReserveProofResult struct {
+	Good  bool   `json:"good"`  // States if the inputs proves the reserve.
+	Spent uint64 `json:"spent"` // Amount (in atomic units) proven, that has been spent since.
+	Total uint64 `json:"total"` // Total amount (in atomic units) proven.
+}
+
+type SignatureResult struct {
+	Signature string `json:"signature"`
+}
+
+// SignatureType is the key of an address that a message is signed with.
+type SignatureType string
+
+const (
+	// SignatureSpend signs with the spend key (the default).
+	SignatureSpend SignatureType = "spend"
+
+	// SignatureView signs with the view key, letting view-only wallets
+	// sign too.
+	SignatureView SignatureType = "view"
+)
+
+type SignParams struct {
+	Data          string        `json:"data"`                     // Anything you need to sign.
+	AccountIndex  uint          `json:"account_index,omitempty"`  // Account of the address to sign with.
+	AddressIndex  uint          `json:"address_index,omitempty"`  // Index of the address within the account to sign with.
+	SignatureType SignatureType `json:"signature_type,omitempty"` // Key to sign with, SignatureSpend if unset.
+}
+
+type VerifyParams struct {
+	Data      string `json:"data"`      // What should have been signed.
+	Address   string `json:"address"`   // Public address of the wallet used to sign the data.
+	Signature string `json:"signature"` // Signature generated by the Sign method.
+}
+
+type VerifyResult struct {
+	Good          bool          `json:"good"`           // True if the signature is valid.
+	Version       uint          `json:"version"`        // Version of the signature.
+	Old           bool          `json:"old"`            // True if the signature uses the old, deprecated format.
+	SignatureType SignatureType `json:"signature_type"` // Key that the data was signed with.
+}
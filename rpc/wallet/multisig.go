@@ -0,0 +1,213 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrMultisigIncomplete is the error returned when a multisig transaction
+// set still isn't fully signed after going through every participant.
+//
+var ErrMultisigIncomplete = errors.New("multisig transaction not fully signed")
+
+// MultisigParticipant is a wallet taking part in a multisig setup.
+//
+type MultisigParticipant struct {
+	Wallet *Client
+
+	// Password is the password of the wallet, required for making it
+	// multisig.
+	//
+	Password string
+}
+
+// MultisigCoordinator drives the wallets of all the participants of an M-of-N
+// multisig wallet (e.g., a 2-of-3 escrow) through its setup and the signing
+// of transactions, relaying the info they must share with each other.
+//
+// ps.: `monero-wallet-rpc` requires `enable-multisig-experimental` to be set
+// in the wallets for multisig to be used.
+//
+type MultisigCoordinator struct {
+	Participants []MultisigParticipant
+}
+
+// Setup turns the wallets of the participants into an M-of-N multisig wallet
+// (M being `threshold`), going through as many rounds of key exchange as
+// needed, giving the address of the multisig wallet.
+//
+func (m *MultisigCoordinator) Setup(ctx context.Context, threshold uint) (string, error) {
+	n := len(m.Participants)
+
+	if threshold < 2 || threshold > uint(n) {
+		return "", fmt.Errorf("invalid threshold %d for %d participants",
+			threshold, n)
+	}
+
+	infos := make([]string, n)
+
+	for i, p := range m.Participants {
+		resp, err := p.Wallet.PrepareMultisig(ctx)
+		if err != nil {
+			return "", fmt.Errorf("prepare multisig %d: %w", i, err)
+		}
+
+		infos[i] = resp.MultisigInfo
+	}
+
+	next := make([]string, n)
+	addresses := make([]string, n)
+
+	for i, p := range m.Participants {
+		resp, err := p.Wallet.MakeMultisig(ctx, MakeMultisigParams{
+			MultisigInfo: others(infos, i),
+			Threshold:    threshold,
+			Password:     p.Password,
+		})
+		if err != nil {
+			return "", fmt.Errorf("make multisig %d: %w", i, err)
+		}
+
+		next[i], addresses[i] = resp.MultisigInfo, resp.Address
+	}
+
+	// the number of rounds depends on the threshold and the version of
+	// the wallet, so exchange keys until they're all ready, bounded by
+	// the N - M + 1 rounds of the key exchange plus that of
+	// verification.
+	//
+	for round := 0; ; round++ {
+		ready, err := m.ready(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		if ready {
+			break
+		}
+
+		if round > n-int(threshold)+1 {
+			return "", fmt.Errorf("not ready after %d rounds of "+
+				"key exchange", round)
+		}
+
+		infos, next = next, make([]string, n)
+
+		for i, p := range m.Participants {
+			resp, err := p.Wallet.ExchangeMultisigKeys(ctx,
+				ExchangeMultisigKeysParams{
+					MultisigInfo: others(infos, i),
+					Password:     p.Password,
+				},
+			)
+			if err != nil {
+				return "", fmt.Errorf("exchange multisig keys %d "+
+					"(round %d): %w", i, round+1, err)
+			}
+
+			next[i], addresses[i] = resp.MultisigInfo, resp.Address
+		}
+	}
+
+	for i, address := range addresses {
+		if address != addresses[0] {
+			return "", fmt.Errorf("participant %d has address '%s', "+
+				"expected '%s'", i, address, addresses[0])
+		}
+	}
+
+	return addresses[0], nil
+}
+
+// SyncInfo has every participant import the multisig info exported by all
+// the others, as needed for spending the outputs received since the last
+// sync.
+//
+func (m *MultisigCoordinator) SyncInfo(ctx context.Context) error {
+	infos := make([]string, len(m.Participants))
+
+	for i, p := range m.Participants {
+		resp, err := p.Wallet.ExportMultisigInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("export multisig info %d: %w", i, err)
+		}
+
+		infos[i] = resp.Info
+	}
+
+	for i, p := range m.Participants {
+		if _, err := p.Wallet.ImportMultisigInfo(ctx, others(infos, i)); err != nil {
+			return fmt.Errorf("import multisig info %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// SignAndSubmit has the participants other than `creator` (the index of the
+// participant that created `multisigTxset`, as in
+// `TransferResult.MultisigTxset`, thus having already signed it) sign it in
+// turn until enough signatures are gathered, submitting it then.
+//
+func (m *MultisigCoordinator) SignAndSubmit(
+	ctx context.Context, creator int, multisigTxset string,
+) (*SubmitMultisigResult, error) {
+	txset := multisigTxset
+
+	for i, p := range m.Participants {
+		if i == creator {
+			continue
+		}
+
+		signed, err := p.Wallet.SignMultisig(ctx, txset)
+		if err != nil {
+			return nil, fmt.Errorf("sign multisig %d: %w", i, err)
+		}
+
+		txset = signed.TxDataHex
+
+		if len(signed.TxHashList) == 0 {
+			continue
+		}
+
+		resp, err := p.Wallet.SubmitMultisig(ctx, txset)
+		if err != nil {
+			return nil, fmt.Errorf("submit multisig: %w", err)
+		}
+
+		return resp, nil
+	}
+
+	return nil, ErrMultisigIncomplete
+}
+
+// ready tells whether the wallets of all participants are ready multisig
+// wallets.
+//
+func (m *MultisigCoordinator) ready(ctx context.Context) (bool, error) {
+	for i, p := range m.Participants {
+		resp, err := p.Wallet.IsMultisig(ctx)
+		if err != nil {
+			return false, fmt.Errorf("is multisig %d: %w", i, err)
+		}
+
+		if !resp.Ready {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// others gives `infos` without the one at index `i` (that of the participant
+// they're handed to).
+//
+func others(infos []string, i int) []string {
+	res := make([]string, 0, len(infos)-1)
+
+	res = append(res, infos[:i]...)
+	res = append(res, infos[i+1:]...)
+
+	return res
+}
@@ -0,0 +1,172 @@
+package wallet_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/duggavo/go-monero/rpc/wallet"
+)
+
+// multisigWallet mimics a participant of a multisig wallet becoming ready
+// after `rounds` rounds of key exchange, and a multisig transaction being
+// fully signed after `threshold` signatures.
+type multisigWallet struct {
+	name      string
+	rounds    int
+	threshold int
+
+	exchanged int
+	received  [][]string
+}
+
+func (w *multisigWallet) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var fields struct {
+		MultisigInfo []string `json:"multisig_info"`
+		Info         []string `json:"info"`
+		TxDataHex    string   `json:"tx_data_hex"`
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+
+	var body interface{}
+
+	switch method {
+	case "prepare_multisig":
+		body = map[string]string{"multisig_info": w.name + "-0"}
+	case "make_multisig", "exchange_multisig_keys":
+		if method == "exchange_multisig_keys" {
+			w.exchanged++
+		}
+
+		w.received = append(w.received, fields.MultisigInfo)
+		body = map[string]string{
+			"address":       "multisig-address",
+			"multisig_info": fmt.Sprintf("%s-%d", w.name, w.exchanged+1),
+		}
+	case "is_multisig":
+		body = map[string]bool{
+			"multisig": true,
+			"ready":    w.exchanged >= w.rounds,
+		}
+	case "export_multisig_info":
+		body = map[string]string{"info": "info-" + w.name}
+	case "import_multisig_info":
+		w.received = append(w.received, fields.Info)
+		body = map[string]int{"n_outputs": len(fields.Info)}
+	case "sign_multisig":
+		signed := fields.TxDataHex + "+" + w.name
+		resp := map[string]interface{}{"tx_data_hex": signed}
+
+		if strings.Count(signed, "+") >= w.threshold-1 {
+			resp["tx_hash_list"] = []string{"hash"}
+		}
+
+		body = resp
+	case "submit_multisig":
+		body = map[string][]string{"tx_hash_list": {fields.TxDataHex}}
+	default:
+		return fmt.Errorf("unexpected method %s", method)
+	}
+
+	b, err = json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, result)
+}
+
+func newMultisigCoordinator(
+	n, rounds, threshold int,
+) (*wallet.MultisigCoordinator, []*multisigWallet) {
+	coordinator := &wallet.MultisigCoordinator{}
+	wallets := make([]*multisigWallet, n)
+
+	for i := range wallets {
+		wallets[i] = &multisigWallet{
+			name:      string(rune('a' + i)),
+			rounds:    rounds,
+			threshold: threshold,
+		}
+
+		coordinator.Participants = append(coordinator.Participants,
+			wallet.MultisigParticipant{
+				Wallet:   wallet.NewClient(wallets[i]),
+				Password: "password",
+			},
+		)
+	}
+
+	return coordinator, wallets
+}
+
+func TestMultisigCoordinator(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("setup", func(t *testing.T) {
+		coordinator, wallets := newMultisigCoordinator(3, 2, 2)
+
+		address, err := coordinator.Setup(ctx, 2)
+		require.NoError(t, err)
+		assert.Equal(t, "multisig-address", address)
+
+		// make_multisig and two rounds of exchange, each with the info
+		// of the others from the previous round.
+		assert.Equal(t, [][]string{
+			{"a-0", "c-0"},
+			{"a-1", "c-1"},
+			{"a-2", "c-2"},
+		}, wallets[1].received)
+	})
+
+	t.Run("setup never ready", func(t *testing.T) {
+		coordinator, _ := newMultisigCoordinator(3, 100, 2)
+
+		_, err := coordinator.Setup(ctx, 2)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid threshold", func(t *testing.T) {
+		coordinator, _ := newMultisigCoordinator(3, 2, 2)
+
+		_, err := coordinator.Setup(ctx, 4)
+		assert.Error(t, err)
+	})
+
+	t.Run("sync info", func(t *testing.T) {
+		coordinator, wallets := newMultisigCoordinator(3, 2, 2)
+
+		require.NoError(t, coordinator.SyncInfo(ctx))
+		assert.Equal(t, [][]string{{"info-b", "info-c"}},
+			wallets[0].received)
+	})
+
+	t.Run("sign and submit", func(t *testing.T) {
+		coordinator, _ := newMultisigCoordinator(3, 2, 3)
+
+		resp, err := coordinator.SignAndSubmit(ctx, 1, "txset")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"txset+a+c"}, resp.TxHashList)
+	})
+
+	t.Run("sign incomplete", func(t *testing.T) {
+		coordinator, _ := newMultisigCoordinator(3, 2, 4)
+
+		_, err := coordinator.SignAndSubmit(ctx, 0, "txset")
+		assert.ErrorIs(t, err, wallet.ErrMultisigIncomplete)
+	})
+}
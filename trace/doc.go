@@ -0,0 +1,38 @@
+// Package trace defines the minimal tracing interfaces that clients from this
+// module create spans through, letting any tracing library (e.g.,
+// OpenTelemetry) be plugged in via a small adapter without this module
+// depending on it.
+//
+// A tracer is either supplied to a client (e.g., `rpc.WithTracer`) or carried
+// by the context of a call (see `ContextWithTracer`), and spans are started
+// from the caller's context so that they nest under whatever span the caller
+// has open.
+//
+// An adapter for OpenTelemetry looks like:
+//
+//	type otelTracer struct{ t oteltrace.Tracer }
+//
+//	func (o otelTracer) Start(
+//		ctx context.Context, name string, attrs ...trace.Attribute,
+//	) (context.Context, trace.Span) {
+//		ctx, span := o.t.Start(ctx, name,
+//			oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+//			oteltrace.WithAttributes(convert(attrs)...),
+//		)
+//		return ctx, otelSpan{span}
+//	}
+//
+//	type otelSpan struct{ s oteltrace.Span }
+//
+//	func (o otelSpan) SetAttributes(attrs ...trace.Attribute) {
+//		o.s.SetAttributes(convert(attrs)...)
+//	}
+//
+//	func (o otelSpan) RecordError(err error) {
+//		o.s.RecordError(err)
+//		o.s.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (o otelSpan) End() { o.s.End() }
+//
+package trace
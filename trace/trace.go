@@ -0,0 +1,122 @@
+package trace
+
+import "context"
+
+// Keys of the attributes set on the spans created by this module.
+const (
+	// AttrRPCSystem identifies the RPC system ("monero").
+	AttrRPCSystem = "rpc.system"
+
+	// AttrRPCKind is the kind of request (e.g., "jsonrpc", "raw",
+	// "binary").
+	AttrRPCKind = "monero.rpc.kind"
+
+	// AttrRPCMethod is the JSONRPC method or the endpoint being hit.
+	AttrRPCMethod = "rpc.method"
+
+	// AttrRPCEndpoint is the HTTP endpoint that the request is sent to
+	// (e.g., "/json_rpc").
+	AttrRPCEndpoint = "monero.rpc.endpoint"
+
+	// AttrServerAddress is the address (host and port) of the node.
+	AttrServerAddress = "server.address"
+
+	// AttrRPCStatus is the `status` that the node replied with.
+	AttrRPCStatus = "monero.rpc.status"
+
+	// AttrRPCUntrusted is the `untrusted` flag that the node replied with.
+	AttrRPCUntrusted = "monero.rpc.untrusted"
+
+	// AttrHTTPStatusCode is the status code of an HTTP response.
+	AttrHTTPStatusCode = "http.response.status_code"
+
+	// AttrDigestAuthAttempt is the number of the attempt (1 for the
+	// unauthenticated request, 2 for the one answering the challenge) of a
+	// request made by `http.DigestAuthTransport`.
+	AttrDigestAuthAttempt = "monero.digest_auth.attempt"
+)
+
+// Attribute is a key/value pair describing a span.
+//
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// String builds a string attribute.
+func String(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Bool builds a boolean attribute.
+func Bool(key string, value bool) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Int builds an integer attribute.
+func Int(key string, value int) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// Span is a unit of work being traced.
+//
+type Span interface {
+	// SetAttributes adds (or overrides) attributes of the span.
+	//
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError marks the span as failed with `err`.
+	//
+	RecordError(err error)
+
+	// End finishes the span.
+	//
+	End()
+}
+
+// Tracer creates spans.
+//
+type Tracer interface {
+	// Start creates a span named `name` as a child of the span carried
+	// by `ctx` (if any), returning a context carrying the new one.
+	//
+	Start(ctx context.Context, name string, attrs ...Attribute) (
+		context.Context, Span,
+	)
+}
+
+// tracerKey is the key of the tracer in a context.
+type tracerKey struct{}
+
+// ContextWithTracer returns a copy of `ctx` carrying `tracer`, which spans
+// started via `Start` with it (or any context derived from it) are created
+// with.
+func ContextWithTracer(ctx context.Context, tracer Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, tracer)
+}
+
+// TracerFromContext gives the tracer carried by `ctx`, or nil if none.
+func TracerFromContext(ctx context.Context) Tracer {
+	tracer, _ := ctx.Value(tracerKey{}).(Tracer)
+	return tracer
+}
+
+// Start starts a span with the tracer carried by `ctx`, or a no-op one if
+// the context doesn't carry any.
+func Start(ctx context.Context, name string, attrs ...Attribute) (
+	context.Context, Span,
+) {
+	tracer := TracerFromContext(ctx)
+	if tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return tracer.Start(ctx, name, attrs...)
+}
+
+// noopSpan is a span that does nothing.
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
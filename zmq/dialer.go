@@ -0,0 +1,108 @@
+package zmq
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/go-zeromq/zmq4"
+	"github.com/go-zeromq/zmq4/transport"
+
+	mhttp "github.com/duggavo/go-monero/http"
+)
+
+// transportDialer is the name of the zmq4 transport that connects through
+// the dialer carried by the context of the socket, used by clients
+// configured with `WithDialer`.
+//
+const transportDialer = "go-monero+tcp"
+
+var (
+	registerTransport    sync.Once
+	registerTransportErr error
+)
+
+// dialerKey is the key of the dialer in the context of a socket.
+//
+type dialerKey struct{}
+
+// dialerTransport is a zmq4 transport dialing `tcp` endpoints through the
+// dialer found in the context of the socket.
+//
+type dialerTransport struct{}
+
+// Dial connects to `addr` through the dialer of the socket.
+//
+func (dialerTransport) Dial(
+	ctx context.Context, _ transport.Dialer, addr string,
+) (net.Conn, error) {
+	dialer, ok := ctx.Value(dialerKey{}).(mhttp.Dialer)
+	if !ok {
+		return nil, fmt.Errorf("no dialer in context")
+	}
+
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// Listen implements zmq4's transport, not supporting listening.
+//
+func (dialerTransport) Listen(context.Context, string) (net.Listener, error) {
+	return nil, fmt.Errorf("listen not supported")
+}
+
+// Addr gives the address to dial for the `host:port` endpoint, leaving host
+// names to be resolved by the dialer (e.g., by a proxy).
+//
+func (dialerTransport) Addr(ep string) (string, error) {
+	if _, _, err := net.SplitHostPort(ep); err != nil {
+		return "", fmt.Errorf("split host port '%s': %w", ep, err)
+	}
+
+	return ep, nil
+}
+
+// dialEndpoint gives the endpoint and context that the subscription socket
+// should be created with: for a client with a dialer, `tcp` endpoints are
+// rewritten to go through it, while otherwise endpoints of hidden services
+// are refused, as reaching them requires a proxy.
+//
+func (c *Client) dialEndpoint(ctx context.Context) (
+	context.Context, string, error,
+) {
+	const tcp = "tcp://"
+
+	if !strings.HasPrefix(c.endpoint, tcp) {
+		if c.dialer != nil {
+			return nil, "", fmt.Errorf("dialer not supported for "+
+				"endpoint '%s'", c.endpoint)
+		}
+
+		return ctx, c.endpoint, nil
+	}
+
+	if c.dialer == nil {
+		if mhttp.IsHiddenService(strings.TrimPrefix(c.endpoint, tcp)) {
+			return nil, "", fmt.Errorf("endpoint '%s': %w",
+				c.endpoint, mhttp.ErrHiddenServiceWithoutProxy)
+		}
+
+		return ctx, c.endpoint, nil
+	}
+
+	registerTransport.Do(func() {
+		registerTransportErr = zmq4.RegisterTransport(
+			transportDialer, dialerTransport{},
+		)
+	})
+	if registerTransportErr != nil {
+		return nil, "", fmt.Errorf("register transport: %w",
+			registerTransportErr)
+	}
+
+	ctx = context.WithValue(ctx, dialerKey{}, c.dialer)
+	endpoint := transportDialer + "://" + strings.TrimPrefix(c.endpoint, tcp)
+
+	return ctx, endpoint, nil
+}
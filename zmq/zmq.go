@@ -7,12 +7,49 @@ import (
 	"fmt"
 
 	"github.com/go-zeromq/zmq4"
+
+	mhttp "github.com/duggavo/go-monero/http"
+	"github.com/duggavo/go-monero/metrics"
 )
 
 type Client struct {
 	endpoint string
 	topic    Topic
 	sub      zmq4.Socket
+	recorder metrics.ZMQRecorder
+	dialer   mhttp.Dialer
+}
+
+// clientOptions is a set of options that can be overridden to tweak the
+// client's behavior.
+//
+type clientOptions struct {
+	Recorder metrics.ZMQRecorder
+	Dialer   mhttp.Dialer
+}
+
+// ClientOption defines a functional option for overriding optional client
+// configuration parameters.
+//
+type ClientOption func(o *clientOptions)
+
+// WithRecorder is a functional option for recording metrics about the
+// messages received (and failures to process them) to `v`.
+//
+func WithRecorder(v metrics.ZMQRecorder) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Recorder = v
+	}
+}
+
+// WithDialer is a functional option for connecting to `tcp://` endpoints
+// through `v` (e.g., one going through a SOCKS5 proxy, as built by
+// `http.NewDialer`), which is required for reaching hidden services.
+//
+func WithDialer(v mhttp.Dialer) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		o.Dialer = v
+	}
 }
 
 // NewClient instantiates a new client that will receive monerod's zmq events.
@@ -28,10 +65,18 @@ type Client struct {
 //	`endpoint` should be 'tcp://127.0.0.1:18085'.
 //
 //
-func NewClient(endpoint string, topic Topic) *Client {
+func NewClient(endpoint string, topic Topic, opts ...ClientOption) *Client {
+	options := &clientOptions{}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
 	return &Client{
 		endpoint: endpoint,
 		topic:    topic,
+		recorder: options.Recorder,
+		dialer:   options.Dialer,
 	}
 }
 
@@ -93,9 +138,14 @@ func (c *Client) Close() error {
 }
 
 func (c *Client) listen(ctx context.Context, topic Topic) error {
+	ctx, endpoint, err := c.dialEndpoint(ctx)
+	if err != nil {
+		return fmt.Errorf("dial endpoint: %w", err)
+	}
+
 	c.sub = zmq4.NewSub(ctx)
 
-	err := c.sub.Dial(c.endpoint)
+	err = c.sub.Dial(endpoint)
 	if err != nil {
 		return fmt.Errorf("dial '%s': %w", c.endpoint, err)
 	}
@@ -112,11 +162,14 @@ func (c *Client) loop(stream *Stream) error {
 	for {
 		msg, err := c.sub.Recv()
 		if err != nil {
+			c.observe(err)
 			return fmt.Errorf("recv: %w", err)
 		}
 
 		for _, frame := range msg.Frames {
 			err := c.ingestFrameArray(stream, frame)
+			c.observe(err)
+
 			if err != nil {
 				return fmt.Errorf("consume frame: %w", err)
 			}
@@ -124,6 +177,21 @@ func (c *Client) loop(stream *Stream) error {
 	}
 }
 
+// observe records the outcome of receiving a message, if configured to.
+//
+func (c *Client) observe(err error) {
+	if c.recorder == nil {
+		return
+	}
+
+	if err != nil {
+		c.recorder.ObserveZMQError(string(c.topic))
+		return
+	}
+
+	c.recorder.ObserveZMQMessage(string(c.topic))
+}
+
 func (c *Client) ingestFrameArray(stream *Stream, frame []byte) error {
 	topic, gson, err := jsonFromFrame(frame)
 	if err != nil {
@@ -1,11 +1,15 @@
 package zmq_test
 
 import (
+	"context"
+	"errors"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	mhttp "github.com/duggavo/go-monero/http"
 	"github.com/duggavo/go-monero/zmq"
 )
 
@@ -62,3 +66,49 @@ func TestJSONFromFrame(t *testing.T) {
 		})
 	}
 }
+
+// dialerFunc adapts a function to mhttp.Dialer.
+type dialerFunc func(ctx context.Context, network, address string) (
+	net.Conn, error,
+)
+
+func (f dialerFunc) DialContext(
+	ctx context.Context, network, address string,
+) (net.Conn, error) {
+	return f(ctx, network, address)
+}
+
+func TestListenWithDialer(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var dialed string
+
+	client := zmq.NewClient("tcp://abc.onion:18083", zmq.TopicMinimalTxPoolAdd,
+		zmq.WithDialer(dialerFunc(func(
+			ctx context.Context, network, address string,
+		) (net.Conn, error) {
+			dialed = address
+			cancel()
+
+			return nil, errors.New("unreachable")
+		})),
+	)
+	defer client.Close()
+
+	_, err := client.Listen(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "abc.onion:18083", dialed)
+}
+
+func TestListenHiddenServiceWithoutDialer(t *testing.T) {
+	t.Parallel()
+
+	client := zmq.NewClient("tcp://abc.onion:18083", zmq.TopicMinimalTxPoolAdd)
+	defer client.Close()
+
+	_, err := client.Listen(context.Background())
+	assert.True(t, errors.Is(err, mhttp.ErrHiddenServiceWithoutProxy))
+}